@@ -0,0 +1,549 @@
+package atom
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxFeedSize is the max size of the remote feeds read by Aggregator
+const maxFeedSize = 10 << 20
+
+// FetchTimeout is the default timeout of the requests of the remote feeds of Aggregator
+const FetchTimeout = 30 * time.Second
+
+// AggregatorSettings presents the settings of the Aggregator
+type AggregatorSettings struct {
+	// Feeds are the remote Atom and RSS feeds to aggregate
+	Feeds []*RemoteFeed `json:"feeds,omitempty"`
+	// CachePath is the directory where the fetched feeds are cached
+	CachePath string `json:"cache_path,omitempty"`
+	// EntryLimit is the max number of entries of the aggregated feed, defaults to EntryLimit
+	EntryLimit int `json:"entry_limit,omitempty"`
+}
+
+// RemoteFeed is a remote Atom or RSS feed
+type RemoteFeed struct {
+	URL string `json:"url"`
+	// Title overrides the title of the feed in the Source of its entries
+	Title string `json:"title,omitempty"`
+}
+
+// FetchError is a failed fetch of a RemoteFeed
+type FetchError struct {
+	URL string
+	Err error
+}
+
+// Error returns the error message of the FetchError
+func (fetchError *FetchError) Error() string {
+	return fmt.Sprintf("fetch %v: %v", fetchError.URL, fetchError.Err)
+}
+
+// FetchErrors are all the failed fetches of Aggregator.Entries
+type FetchErrors []*FetchError
+
+// Error returns the error messages of all the FetchErrors
+func (fetchErrors FetchErrors) Error() string {
+	messages := make([]string, len(fetchErrors))
+	for i, fetchError := range fetchErrors {
+		messages[i] = fetchError.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Aggregator is a "main struct", which combines the entries of the remote feeds in Settings.Aggregator
+// into a single feed, like a blogroll or "planet"
+type Aggregator struct {
+	Settings *Settings
+	// Client fetches the remote feeds, its Timeout is FetchTimeout by default,
+	// so a remote feed that doesn't respond fails like any other fetch instead of blocking
+	Client *http.Client
+	// Policy sanitizes the content of the remote entries, so their markup can be published under your site.
+	// It's DefaultPolicy by default, see sanitizeContent.
+	Policy *Policy
+}
+
+// NewAggregator returns a new instance of Aggregator
+func NewAggregator(settings *Settings) *Aggregator {
+	return &Aggregator{Settings: settings, Client: &http.Client{Timeout: FetchTimeout}, Policy: DefaultPolicy()}
+}
+
+// Render returns the Atom xml data of a feed of the aggregated entries, see Entries.
+// Like Entries, it returns the data with FetchErrors when some of the feeds fail.
+func (aggregator *Aggregator) Render(feedName, selfURL, logoURL string) ([]byte, error) {
+	entries, fetchErr := aggregator.Entries()
+	if fetchErr != nil && len(entries) == 0 {
+		return nil, fetchErr
+	}
+
+	atomRenderer := NewRenderer(aggregator.Settings)
+	lastUpdated := aggregator.Settings.Now()
+	for i, entry := range entries {
+		if i == 0 || entry.Updated.After(lastUpdated) {
+			lastUpdated = entry.Updated
+		}
+	}
+	feed := atomRenderer.NewFeed(feedName, lastUpdated, selfURL, logoURL)
+	feed.Entries = entries
+
+	bytes, err := feed.Marhshall()
+	if err != nil {
+		return nil, err
+	}
+	return bytes, fetchErr
+}
+
+// Entries fetches the feeds, converts their entries to Entry with a Source and returns them newest first,
+// without duplicate IDs and limited to AggregatorSettings.EntryLimit.
+//
+// Feeds are fetched with ETag and Last-Modified, and cached in AggregatorSettings.CachePath.
+// When a fetch fails, the cached feed is used. The feeds that fail without a cache are
+// skipped and returned as FetchErrors, along with the entries of the other feeds.
+func (aggregator *Aggregator) Entries() ([]*Entry, error) {
+	settings := aggregator.aggregatorSettings()
+	if settings.CachePath != "" {
+		err := os.MkdirAll(settings.CachePath, 0755)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var entries []*Entry
+	var fetchErrors FetchErrors
+	for _, remoteFeed := range settings.Feeds {
+		feedEntries, err := aggregator.fetchEntries(remoteFeed)
+		if err != nil {
+			fetchErrors = append(fetchErrors, &FetchError{remoteFeed.URL, err})
+			continue
+		}
+		entries = append(entries, feedEntries...)
+	}
+
+	entries = sortEntries(entries, settings.EntryLimit)
+	if len(fetchErrors) == 0 {
+		return entries, nil
+	}
+	return entries, fetchErrors
+}
+
+func (aggregator *Aggregator) aggregatorSettings() *AggregatorSettings {
+	if aggregator.Settings.Aggregator == nil {
+		return &AggregatorSettings{}
+	}
+	return aggregator.Settings.Aggregator
+}
+
+func (aggregator *Aggregator) fetchEntries(remoteFeed *RemoteFeed) ([]*Entry, error) {
+	entries, err := aggregator.fetch(remoteFeed.URL)
+	if err != nil {
+		return nil, err
+	}
+	if remoteFeed.Title != "" {
+		for _, entry := range entries {
+			entry.Source.Title = remoteFeed.Title
+		}
+	}
+	dateEntries(entries, aggregator.Settings.Now())
+	err = aggregator.sanitizeEntries(entries)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// sanitizeEntries sanitizes the Content of the entries with the Policy, if there is one
+func (aggregator *Aggregator) sanitizeEntries(entries []*Entry) error {
+	if aggregator.Policy == nil {
+		return nil
+	}
+	for _, entry := range entries {
+		content, err := sanitizeContent(aggregator.Policy, entry.Content)
+		if err != nil {
+			return err
+		}
+		entry.Content = content
+	}
+	return nil
+}
+
+// sanitizeContent returns the content sanitized with the policy. Other XML than xhtml can't be sanitized,
+// so it's removed, and content of an invalid type is kept as text.
+func sanitizeContent(policy *Policy, content *EntryContent) (*EntryContent, error) {
+	if content == nil {
+		return nil, nil
+	}
+	kind, err := content.kind()
+	if err != nil {
+		return &EntryContent{Content: content.Content, Type: "text"}, nil
+	}
+
+	switch {
+	case kind == xmlContent:
+		return nil, nil
+	case kind == xhtmlContent, kind == textContent && content.Type == "html":
+		var sanitized string
+		sanitized, _, err = policy.Sanitize(content.Content)
+		if err != nil {
+			return nil, err
+		}
+		if kind == xhtmlContent {
+			return NewXHTMLContent(sanitized)
+		}
+		return &EntryContent{Content: sanitized, Type: "html"}, nil
+	}
+	return content, nil
+}
+
+// dateEntries sets the dates of the entries and their Source that the remote feed doesn't have to fetched,
+// so they aren't outputted as 0001-01-01. Published defaults to Updated.
+func dateEntries(entries []*Entry, fetched time.Time) {
+	for _, entry := range entries {
+		if entry.Updated.IsZero() {
+			entry.Updated = fetched
+		}
+		if entry.Published.IsZero() {
+			entry.Published = entry.Updated
+		}
+		if entry.Source.Updated.IsZero() {
+			entry.Source.Updated = fetched
+		}
+	}
+}
+
+// sortEntries returns the entries newest first, without duplicate IDs and at most limit entries.
+// A limit <= 0 defaults to EntryLimit.
+func sortEntries(entries []*Entry, limit int) []*Entry {
+	if limit <= 0 {
+		limit = EntryLimit
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entryDate(entries[i]).After(entryDate(entries[j]))
+	})
+
+	seen := map[string]bool{}
+	sorted := make([]*Entry, 0, len(entries))
+	for _, entry := range entries {
+		if len(sorted) == limit {
+			break
+		}
+		if seen[entry.ID] {
+			continue
+		}
+		seen[entry.ID] = true
+		sorted = append(sorted, entry)
+	}
+	return sorted
+}
+
+func entryDate(entry *Entry) time.Time {
+	if entry.Published.IsZero() {
+		return entry.Updated
+	}
+	return entry.Published
+}
+
+// ParseEntries parses the Atom or RSS xml data of the feed at feedURL into entries with a Source
+func ParseEntries(feedURL string, data []byte) ([]*Entry, error) {
+	root, err := rootName(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch root {
+	case "feed":
+		return parseAtomEntries(data)
+	case "rss":
+		return parseRSSEntries(feedURL, data)
+	}
+	return nil, fmt.Errorf("unknown feed root element: <%v>", root)
+}
+
+func parseAtomEntries(data []byte) ([]*Entry, error) {
+	feed, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return feed.sourcedEntries(), nil
+}
+
+func parseRSSEntries(feedURL string, data []byte) ([]*Entry, error) {
+	rss, err := ParseRSS(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return rss.sourcedEntries(feedURL), nil
+}
+
+func rootName(data []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.RawToken()
+		if err != nil {
+			return "", err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+// sourcedEntries returns the entries of the feed with their Source set to the feed, unless they already have one
+func (feed *Feed) sourcedEntries() []*Entry {
+	source := &Source{ID: feed.ID, Title: feed.Title, Updated: feed.Updated, Authors: feed.Authors}
+	for _, link := range feed.Links {
+		if link.Rel == "self" || link.Rel == "alternate" {
+			source.Links = append(source.Links, link)
+		}
+	}
+
+	for _, entry := range feed.Entries {
+		if entry.Source == nil {
+			copied := *source
+			entry.Source = &copied
+		}
+	}
+	return feed.Entries
+}
+
+// sourcedEntries returns the items of the RSS document at feedURL as entries with a Source.
+// The feedURL is replaced by the channel's rel="self" atom:link when there is one.
+// Items without a pubDate are dated with the lastBuildDate or pubDate of the channel
+// and items without a guid and link get an ID from the feedURL, see hashID.
+func (rss *RSS) sourcedEntries(feedURL string) []*Entry {
+	channel := rss.Channel
+	if channel == nil {
+		return nil
+	}
+	if channel.AtomLink != nil && channel.AtomLink.Rel == "self" && channel.AtomLink.Href != "" {
+		feedURL = channel.AtomLink.Href
+	}
+
+	channelDate := channel.date()
+	entries := make([]*Entry, len(channel.Items))
+	for i, item := range channel.Items {
+		entries[i] = item.toEntry(channelDate)
+		if entries[i].ID == "" {
+			entries[i].ID = item.hashID(feedURL)
+		}
+	}
+
+	source := &Source{
+		ID:    feedURL,
+		Title: channel.Title,
+		Links: []*Link{
+			{Rel: "self", Type: RSSMediaType, Href: feedURL},
+			{Rel: "alternate", Type: "text/html", Href: channel.Link},
+		},
+	}
+	source.Updated = channelDate
+	for _, entry := range entries {
+		if entry.Updated.After(source.Updated) {
+			source.Updated = entry.Updated
+		}
+	}
+	for _, entry := range entries {
+		copied := *source
+		entry.Source = &copied
+	}
+	return entries
+}
+
+// date returns the lastBuildDate of the channel, or its pubDate when there is none
+func (channel *RSSChannel) date() time.Time {
+	date := rssDateOrZero(channel.LastBuildDate)
+	if date.IsZero() {
+		return rssDateOrZero(channel.PubDate)
+	}
+	return date
+}
+
+// toEntry converts the RSS item to an Entry, the reverse of HTMLEntry.ToRSSItem.
+// Items without a pubDate are dated with the channelDate.
+func (item *RSSItem) toEntry(channelDate time.Time) *Entry {
+	entry := &Entry{
+		ID:    item.Link,
+		Title: item.Title,
+	}
+	if item.Link != "" {
+		entry.Links = []*Link{{Rel: "alternate", Type: "text/html", Href: item.Link}}
+	}
+	if item.GUID != nil && item.GUID.GUID != "" {
+		entry.ID = strings.TrimSpace(item.GUID.GUID)
+	}
+	entry.Published = rssDateOrZero(item.PubDate)
+	if entry.Published.IsZero() {
+		entry.Published = channelDate
+	}
+	entry.Updated = entry.Published
+
+	for _, creator := range item.Creators {
+		entry.Authors = append(entry.Authors, &Author{Name: creator})
+	}
+	if item.ContentEncoded != nil {
+		entry.Content = &EntryContent{Content: item.ContentEncoded.Content, Type: "html"}
+		entry.Summary = item.Description
+	} else if item.Description != "" {
+		entry.Content = &EntryContent{Content: item.Description, Type: "html"}
+	}
+	return entry
+}
+
+// hashID returns an ID for the item of the feed at feedURL, for items without a guid and link to use as their ID.
+// It's the feedURL with a hash of the item as the fragment, so it's the same each time the item is fetched.
+func (item *RSSItem) hashID(feedURL string) string {
+	content := ""
+	if item.ContentEncoded != nil {
+		content = item.ContentEncoded.Content
+	}
+	hash := sha1.Sum([]byte(strings.Join([]string{item.Title, item.PubDate, item.Description, content}, "\n")))
+	return feedURL + "#" + hex.EncodeToString(hash[:])
+}
+
+func rssDateOrZero(value string) time.Time {
+	t, err := ParseRSSDate(value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+type feedCache struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Data         []byte `json:"data"`
+}
+
+func (aggregator *Aggregator) cacheFilePath(url string) string {
+	cachePath := aggregator.aggregatorSettings().CachePath
+	if cachePath == "" {
+		return ""
+	}
+	hash := sha1.Sum([]byte(url))
+	return path.Join(cachePath, hex.EncodeToString(hash[:])+".json")
+}
+
+func (aggregator *Aggregator) readCache(url string) *feedCache {
+	filePath := aggregator.cacheFilePath(url)
+	if filePath == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil
+	}
+	cache := &feedCache{}
+	err = json.Unmarshal(data, cache)
+	if err != nil {
+		return nil
+	}
+	return cache
+}
+
+func (aggregator *Aggregator) writeCache(url string, cache *feedCache) error {
+	filePath := aggregator.cacheFilePath(url)
+	if filePath == "" {
+		return nil
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, data, 0644)
+}
+
+// fetch returns the entries of the feed at url, using the cache when it's not modified or the request fails.
+// The cache is only replaced by feeds that parse, so it's still there when the remote responds with an error page.
+func (aggregator *Aggregator) fetch(url string) ([]*Entry, error) {
+	cache := aggregator.readCache(url)
+	fetched, err := aggregator.request(url, cache)
+	if err == nil && fetched == cache {
+		return ParseEntries(url, cache.Data)
+	}
+
+	var entries []*Entry
+	if err == nil {
+		entries, err = ParseEntries(url, fetched.Data)
+	}
+	if err != nil {
+		if cache != nil {
+			return ParseEntries(url, cache.Data)
+		}
+		return nil, err
+	}
+
+	err = aggregator.writeCache(url, fetched)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// request GETs url conditionally on the cache, returning the cache when the response is 304 Not Modified
+func (aggregator *Aggregator) request(url string, cache *feedCache) (*feedCache, error) {
+	request, err := newConditionalRequest(url, cache)
+	if err != nil {
+		return nil, err
+	}
+	response, err := aggregator.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	data, err := readFeed(response.Body)
+	closeErr := response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if closeErr != nil {
+		return nil, closeErr
+	}
+
+	switch {
+	case response.StatusCode == http.StatusNotModified && cache != nil:
+		return cache, nil
+	case response.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("responded with %v", response.Status)
+	}
+	return &feedCache{
+		ETag:         response.Header.Get("ETag"),
+		LastModified: response.Header.Get("Last-Modified"),
+		Data:         data,
+	}, nil
+}
+
+// readFeed reads the body of a feed response, returning an error when it's larger than maxFeedSize
+func readFeed(body io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(body, maxFeedSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFeedSize {
+		return nil, fmt.Errorf("feed is larger than the max size of %v bytes", maxFeedSize)
+	}
+	return data, nil
+}
+
+func newConditionalRequest(url string, cache *feedCache) (*http.Request, error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil || cache == nil {
+		return request, err
+	}
+	if cache.ETag != "" {
+		request.Header.Set("If-None-Match", cache.ETag)
+	}
+	if cache.LastModified != "" {
+		request.Header.Set("If-Modified-Since", cache.LastModified)
+	}
+	return request, nil
+}
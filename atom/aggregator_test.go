@@ -0,0 +1,499 @@
+package atom
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+const testETag = `"v1"`
+
+type remoteServer struct {
+	mutex    sync.Mutex
+	requests int
+	modified int
+	fixtures map[string]string
+	// errorPage responds with a HTML error page with status 200 to all requests
+	errorPage bool
+}
+
+func newRemoteServer(t *testing.T) (*remoteServer, *httptest.Server) {
+	remote := &remoteServer{
+		fixtures: map[string]string{
+			"/posts.atom":    "feed0.xml",
+			"/dup.atom":      "feed0.xml",
+			"/posts.rss":     "rss0.xml",
+			"/colleague.rss": "aggregator_remote.rss",
+			"/podcast.atom":  "aggregator_remote.atom",
+			"/text.atom":     "aggregator_text.atom",
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remote.mutex.Lock()
+		defer remote.mutex.Unlock()
+		remote.requests++
+
+		if remote.errorPage {
+			_, err := w.Write([]byte("<html><body>Something went wrong</body></html>"))
+			if err != nil {
+				t.Error(err)
+			}
+			return
+		}
+		fixture, contains := remote.fixtures[r.URL.Path]
+		if !contains {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("If-None-Match") == testETag {
+			remote.modified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", testETag)
+		_, err := w.Write(test.ReadFixture(t, fixture))
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	return remote, server
+}
+
+func defaultAggregator(t *testing.T, serverURL string, paths ...string) (*Aggregator, func()) {
+	cachePath, clean := test.SandboxDir(t, "feeds")
+	settings := DefaultSettings()
+	settings.Clock = testClock
+	settings.Aggregator = &AggregatorSettings{CachePath: cachePath}
+	for _, p := range paths {
+		settings.Aggregator.Feeds = append(settings.Aggregator.Feeds, &RemoteFeed{URL: serverURL + p})
+	}
+	return NewAggregator(settings), clean
+}
+
+func TestAggregator_Render(t *testing.T) {
+	_, server := newRemoteServer(t)
+	defer server.Close()
+
+	aggregator, clean := defaultAggregator(t, server.URL, "/posts.atom", "/dup.atom", "/colleague.rss")
+	defer clean()
+	aggregator.Settings.Aggregator.Feeds[2].Title = "A Colleague"
+	aggregator.Settings.Aggregator.EntryLimit = 4
+
+	bytes, err := aggregator.Render("planet", "planet.atom", "logo.png")
+	if err != nil {
+		t.Error(err)
+	}
+
+	got := string(bytes)
+	fixtureFilename := "aggregator.xml"
+	if *updateFixturesPtr {
+		test.WriteFixture(t, fixtureFilename, []byte(got))
+		return
+	}
+
+	exp := string(test.ReadFixture(t, fixtureFilename))
+	if got != exp {
+		t.Error(test.NewContext().DiffString("Aggregator.Render", got, exp, cmp.Diff(got, exp)))
+	}
+}
+
+func TestAggregator_Render_Namespaces(t *testing.T) {
+	_, server := newRemoteServer(t)
+	defer server.Close()
+
+	aggregator, clean := defaultAggregator(t, server.URL, "/podcast.atom")
+	defer clean()
+
+	bytes, err := aggregator.Render("planet", "planet.atom", "logo.png")
+	if err != nil {
+		t.Error(err)
+	}
+
+	got := string(bytes)
+	fixtureFilename := "aggregator_namespaces.xml"
+	if *updateFixturesPtr {
+		test.WriteFixture(t, fixtureFilename, []byte(got))
+		return
+	}
+
+	exp := string(test.ReadFixture(t, fixtureFilename))
+	if got != exp {
+		t.Error(test.NewContext().DiffString("Aggregator.Render", got, exp, cmp.Diff(got, exp)))
+	}
+
+	feed, err := Parse(strings.NewReader(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := feed.Entries[0]
+	test.AssertLabel(t, "InReplyTo", len(entry.InReplyTo), 1)
+	test.AssertLabel(t, "Duration", entry.PodcastEpisode.Duration, "12:34")
+	test.AssertLabel(t, "ThreadCount", *entry.Links[1].ThreadCount, 2)
+}
+
+func TestAggregator_Render_TextConstructs(t *testing.T) {
+	_, server := newRemoteServer(t)
+	defer server.Close()
+
+	aggregator, clean := defaultAggregator(t, server.URL, "/text.atom")
+	defer clean()
+
+	entries, err := aggregator.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name string
+		got  string
+		exp  string
+	}{
+		{"Source.Title", entries[0].Source.Title, "Texter & Co"},
+		{"Entries[0].Title", entries[0].Title, "T < 2"},
+		{"Entries[0].Summary", entries[0].Summary, "The summary"},
+		{"Entries[1].Title", entries[1].Title, "XHTML"},
+		{"Entries[2].Title", entries[2].Title, "a <b> b"},
+	}
+	for _, tc := range testCases {
+		test.AssertLabel(t, tc.name, tc.got, tc.exp)
+	}
+
+	bytes, err := aggregator.Render("planet", "planet.atom", "logo.png")
+	if err != nil {
+		t.Error(err)
+	}
+
+	got := string(bytes)
+	fixtureFilename := "aggregator_text.xml"
+	if *updateFixturesPtr {
+		test.WriteFixture(t, fixtureFilename, []byte(got))
+		return
+	}
+
+	exp := string(test.ReadFixture(t, fixtureFilename))
+	if got != exp {
+		t.Error(test.NewContext().DiffString("Aggregator.Render", got, exp, cmp.Diff(got, exp)))
+	}
+}
+
+func entryIDs(entries []*Entry) []string {
+	ids := make([]string, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
+	}
+	return ids
+}
+
+func TestAggregator_Entries(t *testing.T) {
+	_, server := newRemoteServer(t)
+	defer server.Close()
+
+	aggregator, clean := defaultAggregator(t, server.URL, "/posts.rss", "/colleague.rss")
+	defer clean()
+
+	entries, err := aggregator.Entries()
+	if err != nil {
+		t.Error(err)
+	}
+	exp := []string{
+		"https://yourwebsite.com/third",
+		"https://yourwebsite.com/second",
+		"https://colleague.com/hello",
+		"https://yourwebsite.com/first",
+		"colleague-old-post",
+	}
+	got := entryIDs(entries)
+	if !cmp.Equal(got, exp) {
+		t.Error(test.NewContext().DiffString("IDs", got, exp, cmp.Diff(got, exp)))
+	}
+
+	entry := entries[2]
+	test.AssertLabel(t, "Content", entry.Content.Content, "<p>Hi there</p>")
+	test.AssertLabel(t, "Source.ID", entry.Source.ID, "https://colleague.com/feed.rss")
+	test.AssertLabel(t, "Source.Title", entry.Source.Title, "Colleague Blog")
+	test.AssertLabel(t, "Published", entry.Published.Format("2006-01-02T15:04:05"), "2018-01-03T10:00:00")
+	test.AssertLabel(t, "Source.Updated", entry.Source.Updated.Equal(entry.Published), true)
+	test.AssertLabel(t, "Authors", len(entries[0].Authors), 1)
+	test.AssertLabel(t, "Summary", entries[0].Summary, "The sum of the conclusion")
+}
+
+func TestAggregator_Entries_Cache(t *testing.T) {
+	remote, server := newRemoteServer(t)
+	aggregator, clean := defaultAggregator(t, server.URL, "/posts.atom", "/colleague.rss", "/missing.atom")
+	defer clean()
+
+	testCases := []struct {
+		closeServer bool
+		expRequests int
+		expModified int
+	}{
+		{false, 3, 0},
+		{false, 6, 2},
+		{true, 6, 2},
+	}
+
+	expIDs := []string{
+		"yourwebsite.com:third:2018-01-06",
+		"yourwebsite.com:second:2018-01-04",
+		"https://colleague.com/hello",
+		"yourwebsite.com:first:2018-01-02",
+		"colleague-old-post",
+	}
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":       testCaseIndex,
+			"closeServer": tc.closeServer,
+		})
+		if tc.closeServer {
+			server.Close()
+		}
+
+		entries, err := aggregator.Entries()
+		fetchErrors, ok := err.(FetchErrors)
+		if !ok || len(fetchErrors) != 1 || fetchErrors[0].URL != server.URL+"/missing.atom" {
+			t.Error(context.Stringf("unexpected FetchErrors: %v", err))
+		}
+
+		got := entryIDs(entries)
+		if !cmp.Equal(got, expIDs) {
+			t.Error(context.DiffString("IDs", got, expIDs, cmp.Diff(got, expIDs)))
+		}
+		if remote.requests != tc.expRequests {
+			t.Error(context.GotExpString("requests", remote.requests, tc.expRequests))
+		}
+		if remote.modified != tc.expModified {
+			t.Error(context.GotExpString("not modified", remote.modified, tc.expModified))
+		}
+	}
+}
+
+func TestAggregator_Render_Failed(t *testing.T) {
+	_, server := newRemoteServer(t)
+	server.Close()
+
+	aggregator, clean := defaultAggregator(t, server.URL, "/posts.atom")
+	defer clean()
+
+	bytes, err := aggregator.Render("planet", "planet.atom", "logo.png")
+	if _, ok := err.(FetchErrors); !ok {
+		t.Errorf("expected FetchErrors, got: %v", err)
+	}
+	if bytes != nil {
+		t.Errorf("expected no data, got: %v", string(bytes))
+	}
+}
+
+func TestAggregator_Entries_ErrorPage(t *testing.T) {
+	remote, server := newRemoteServer(t)
+	defer server.Close()
+
+	aggregator, clean := defaultAggregator(t, server.URL, "/posts.atom")
+	defer clean()
+
+	for _, errorPage := range []bool{false, true, true} {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"errorPage": errorPage,
+		})
+		remote.errorPage = errorPage
+
+		entries, err := aggregator.Entries()
+		if err != nil {
+			t.Error(context.String(err))
+		}
+		if len(entries) != 3 {
+			t.Error(context.GotExpString("entries", len(entries), 3))
+		}
+	}
+}
+
+func TestAggregator_Entries_MaxFeedSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(strings.Repeat(" ", maxFeedSize+1)))
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	aggregator, clean := defaultAggregator(t, server.URL, "/big.atom")
+	defer clean()
+
+	_, err := aggregator.Entries()
+	if err == nil || !strings.Contains(err.Error(), "larger than the max size") {
+		t.Errorf("expected max size error, got: %v", err)
+	}
+}
+
+func TestAggregator_Entries_Timeout(t *testing.T) {
+	_, server := newRemoteServer(t)
+	defer server.Close()
+	done := make(chan struct{})
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer slowServer.Close()
+	defer close(done)
+
+	aggregator, clean := defaultAggregator(t, server.URL, "/posts.atom")
+	defer clean()
+	test.AssertLabel(t, "Client.Timeout", aggregator.Client.Timeout, FetchTimeout)
+	aggregator.Client.Timeout = 100 * time.Millisecond
+	aggregator.Settings.Aggregator.Feeds = append(aggregator.Settings.Aggregator.Feeds, &RemoteFeed{URL: slowServer.URL + "/slow.atom"})
+
+	entries, err := aggregator.Entries()
+	fetchErrors, ok := err.(FetchErrors)
+	if !ok || len(fetchErrors) != 1 || fetchErrors[0].URL != slowServer.URL+"/slow.atom" {
+		t.Errorf("expected FetchErrors of the slow feed, got: %v", err)
+	}
+	test.AssertLabel(t, "entries", len(entries), 3)
+}
+
+func TestAggregator_Entries_Undated(t *testing.T) {
+	testCases := []struct {
+		channelDates string
+		exp          time.Time
+	}{
+		{"<lastBuildDate>Wed, 3 Jan 2018 10:00:00 GMT</lastBuildDate><pubDate>Tue, 2 Jan 2018 10:00:00 GMT</pubDate>", time.Date(2018, time.January, 3, 10, 0, 0, 0, time.UTC)},
+		{"<pubDate>Tue, 2 Jan 2018 10:00:00 GMT</pubDate>", time.Date(2018, time.January, 2, 10, 0, 0, 0, time.UTC)},
+		{"", testClock()},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":        testCaseIndex,
+			"channelDates": tc.channelDates,
+		})
+
+		data := `<rss version="2.0"><channel><title>Undated</title>` + tc.channelDates +
+			`<item><title>No Date</title><link>https://colleague.com/no-date</link></item></channel></rss>`
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(data))
+			if err != nil {
+				t.Error(err)
+			}
+		}))
+		aggregator, clean := defaultAggregator(t, server.URL, "/undated.rss")
+
+		entries, err := aggregator.Entries()
+		if err != nil {
+			t.Error(context.String(err))
+		}
+		if len(entries) == 1 {
+			entry := entries[0]
+			test.AssertLabel(t, context.String("Published"), entry.Published.UTC(), tc.exp)
+			test.AssertLabel(t, context.String("Updated"), entry.Updated.UTC(), tc.exp)
+			test.AssertLabel(t, context.String("Source.Updated"), entry.Source.Updated.UTC(), tc.exp)
+		} else {
+			t.Error(context.GotExpString("entries", len(entries), 1))
+		}
+
+		bytes, err := aggregator.Render("planet", "planet.atom", "logo.png")
+		if err != nil {
+			t.Error(context.String(err))
+		}
+		if strings.Contains(string(bytes), "0001-01-01") {
+			t.Error(context.String("contains a zero date"))
+		}
+		clean()
+		server.Close()
+	}
+}
+
+func TestAggregator_Entries_Sanitize(t *testing.T) {
+	data := `<feed xmlns="http://www.w3.org/2005/Atom"><id>remote</id><title>Remote</title><updated>2018-01-03T10:00:00Z</updated>
+<entry><id>html</id><updated>2018-01-03T10:00:00Z</updated><content type="html">&lt;p onclick="steal()"&gt;Hi&lt;/p&gt;&lt;script&gt;alert(1)&lt;/script&gt;</content></entry>
+<entry><id>xhtml</id><updated>2018-01-02T10:00:00Z</updated><content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml"><p onclick="steal()">Hi<br/></p><script>alert(1)</script></div></content></entry>
+<entry><id>svg</id><updated>2018-01-01T10:00:00Z</updated><content type="image/svg+xml"><svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg></content></entry>
+</feed>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(data))
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		policy *Policy
+		exp    []*EntryContent
+	}{
+		{DefaultPolicy(), []*EntryContent{
+			{Content: "<p>Hi</p>", Type: "html"},
+			{Content: "<p>Hi<br></br></p>", Type: "xhtml"},
+			nil,
+		}},
+		{nil, []*EntryContent{
+			{Content: `<p onclick="steal()">Hi</p><script>alert(1)</script>`, Type: "html"},
+			{Content: `<p onclick="steal()">Hi<br></br></p><script>alert(1)</script>`, Type: "xhtml"},
+			{Content: `<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`, Type: "image/svg+xml"},
+		}},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+		})
+
+		aggregator, clean := defaultAggregator(t, server.URL, "/remote.atom")
+		aggregator.Policy = tc.policy
+		entries, err := aggregator.Entries()
+		clean()
+		if err != nil {
+			t.Error(context.String(err))
+			continue
+		}
+
+		got := make([]*EntryContent, len(entries))
+		for i, entry := range entries {
+			got[i] = entry.Content
+		}
+		ignoreXMLName := cmpopts.IgnoreFields(EntryContent{}, "XMLName")
+		if !cmp.Equal(got, tc.exp, ignoreXMLName) {
+			t.Error(context.DiffString("Content", got, tc.exp, cmp.Diff(got, tc.exp, ignoreXMLName)))
+		}
+	}
+}
+
+func TestParseEntries_NoID(t *testing.T) {
+	data := []byte(`<rss version="2.0"><channel><title>No IDs</title>
+<item><title>First</title><description>a</description></item>
+<item><title>Second</title><description>b</description></item>
+</channel></rss>`)
+
+	var ids [][]string
+	for i := 0; i < 2; i++ {
+		entries, err := ParseEntries("https://colleague.com/feed.rss", data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = sortEntries(entries, 0)
+		ids = append(ids, entryIDs(entries))
+		for _, entry := range entries {
+			if !strings.HasPrefix(entry.ID, "https://colleague.com/feed.rss#") {
+				t.Errorf("expected an ID from the feed URL, got: %v", entry.ID)
+			}
+			if len(entry.Links) != 0 {
+				t.Errorf("expected no links, got: %v", entry.Links)
+			}
+		}
+	}
+	test.AssertLabel(t, "len(IDs)", len(ids[0]), 2)
+	if !cmp.Equal(ids[0], ids[1]) {
+		t.Error(test.NewContext().DiffString("IDs of each parse", ids[0], ids[1], cmp.Diff(ids[0], ids[1])))
+	}
+}
+
+func TestParseEntries_Unknown(t *testing.T) {
+	_, err := ParseEntries("https://site.com/feed", []byte(`<?xml version="1.0"?><html></html>`))
+	if err == nil {
+		t.Error("expected error for unknown root element")
+	}
+}
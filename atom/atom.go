@@ -4,78 +4,224 @@ Package atom represents your Atom xml data into structs and Marshalls them.
 package atom
 
 import (
+	"bytes"
 	"encoding/xml"
-	"fmt"
-	"strings"
 	"time"
 )
 
+// AtomMediaType is the media type of Atom feeds
+const AtomMediaType = "application/atom+xml"
+
 // Feed represents the entire Atom feed
 type Feed struct {
 	XMLName xml.Name `xml:"feed"`
 
 	XMLLang string `xml:"xml:lang,attr"`
 	XMLNS   string `xml:"xmlns,attr"`
+	XMLNSFH string `xml:"xmlns:fh,attr,omitempty"`
+	// XMLNSITunes is set by SetPodcast
+	XMLNSITunes string `xml:"xmlns:itunes,attr,omitempty"`
+	// XMLNSAT is set by SetDeletedEntries
+	XMLNSAT string `xml:"xmlns:at,attr,omitempty"`
+	// XMLNSThr is set by HTMLEntriesToFeed when the entries reply or have replies
+	XMLNSThr string `xml:"xmlns:thr,attr,omitempty"`
+	// XMLNSExtensions are the xmlns attributes of the namespaces of the Extensions, see DeclareExtensions.
+	// Parse also keeps the other unknown attributes of the feed here.
+	XMLNSExtensions []xml.Attr `xml:",any,attr"`
 
-	ID      string    `xml:"id"`
-	Title   string    `xml:"title"`
-	Updated time.Time `xml:"updated"`
+	// Stylesheet is the href of the XSLT stylesheet in the <?xml-stylesheet?> processing instruction,
+	// which browsers use to show the feed as a HTML page. See DefaultStylesheet.
+	Stylesheet string `xml:"-"`
+
+	ID       string    `xml:"id"`
+	Title    string    `xml:"title"`
+	Subtitle string    `xml:"subtitle,omitempty"`
+	Updated  time.Time `xml:"updated"`
+
+	Icon         string         `xml:"icon"`
+	Logo         string         `xml:"logo,omitempty"`
+	Rights       string         `xml:"rights,omitempty"`
+	Generator    *Generator     `xml:"generator"`
+	Authors      []*Author      `xml:"author"`
+	Contributors []*Contributor `xml:"contributor"`
+	Categories   []*Category    `xml:"category"`
 
-	Icon   string  `xml:"icon"`
-	Author *Author `xml:"author"`
+	// Podcast are the iTunes podcast elements, which are marshalled as children of the feed, see SetPodcast
+	Podcast *Podcast `xml:"itunes:podcast,omitempty"`
 
 	Links   []*Link  `xml:"link"`
-	Entries []*Entry `xml:"entry"`
+	Archive *Archive `xml:"fh:archive"`
+
+	Extensions Extensions `xml:",any"`
+
+	DeletedEntries []*DeletedEntry `xml:"at:deleted-entry"`
+	Entries        []*Entry        `xml:"entry"`
+}
+
+// UnmarshalXML unmarshals the feed, setting the Podcast when the feed has any of its elements.
+// The Text constructs are read as plain text, see textConstruct.
+func (feed *Feed) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	// Fields has the fields of Feed without its methods, it's exported so encoding/xml can set them
+	type Fields Feed
+	data := &struct {
+		*Fields
+		*Podcast
+		Title    *textConstruct `xml:"title"`
+		Subtitle *textConstruct `xml:"subtitle"`
+		Rights   *textConstruct `xml:"rights"`
+	}{Fields: (*Fields)(feed)}
+	err := d.DecodeElement(data, &start)
+	if err != nil {
+		return err
+	}
+	feed.Podcast = data.Podcast
+	feed.Title = data.Title.text()
+	feed.Subtitle = data.Subtitle.text()
+	feed.Rights = data.Rights.text()
+	return nil
 }
 
 // Marhshall returns the Atom xml data of the feed
 func (feed *Feed) Marhshall() ([]byte, error) {
-	bytes, err := xml.MarshalIndent(&feed, "", "  ")
+	buffer := &bytes.Buffer{}
+	err := NewEncoder(buffer).Encode(feed)
 	if err != nil {
 		return nil, err
 	}
-	return append([]byte(xml.Header), bytes...), nil
+	return buffer.Bytes(), nil
 }
 
 // Entry represents an entry in the Atom feed
 type Entry struct {
 	XMLName xml.Name `xml:"entry"`
+	// XMLLang is the language of the entry, when it's different from the Feed.XMLLang
+	XMLLang string `xml:"xml:lang,attr,omitempty"`
 
 	ID      string    `xml:"id"`
 	Title   string    `xml:"title"`
 	Updated time.Time `xml:"updated"`
 
-	Author  *Author       `xml:"author"`
-	Content *EntryContent `xml:"content"`
-	Link    *Link         `xml:"link"`
-	Summary string        `xml:"summary"`
+	Authors      []*Author      `xml:"author"`
+	Contributors []*Contributor `xml:"contributor"`
+	Content      *EntryContent  `xml:"content"`
+	Links        []*Link        `xml:"link"`
+	Categories   []*Category    `xml:"category"`
+	Summary      string         `xml:"summary,omitempty"`
+	// Source is the feed the entry was copied from, see Aggregator
+	Source *Source `xml:"source"`
+	// InReplyTo are the resources the entry replies to
+	InReplyTo []*InReplyTo `xml:"thr:in-reply-to"`
 
 	Published time.Time `xml:"published"`
+
+	*PodcastEpisode
+
+	Extensions Extensions `xml:",any"`
+}
+
+// UnmarshalXML unmarshals the entry, where the Text constructs are read as plain text, see textConstruct
+func (entry *Entry) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type Fields Entry
+	data := &struct {
+		*Fields
+		Title   *textConstruct `xml:"title"`
+		Summary *textConstruct `xml:"summary"`
+	}{Fields: (*Fields)(entry)}
+	err := d.DecodeElement(data, &start)
+	if err != nil {
+		return err
+	}
+	entry.Title = data.Title.text()
+	entry.Summary = data.Summary.text()
+	return nil
+}
+
+// Source represents the metadata of the feed an Entry was copied from (RFC 4287 4.2.11)
+type Source struct {
+	XMLName xml.Name `xml:"source"`
+
+	ID      string    `xml:"id,omitempty"`
+	Title   string    `xml:"title,omitempty"`
+	Updated time.Time `xml:"updated"`
+
+	Authors []*Author `xml:"author"`
+	Links   []*Link   `xml:"link"`
+}
+
+// UnmarshalXML unmarshals the source, where the title is read as plain text, see textConstruct
+func (source *Source) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type Fields Source
+	data := &struct {
+		*Fields
+		Title *textConstruct `xml:"title"`
+	}{Fields: (*Fields)(source)}
+	err := d.DecodeElement(data, &start)
+	if err != nil {
+		return err
+	}
+	source.Title = data.Title.text()
+	return nil
 }
 
 // Author represents an author in the Atom feed
 type Author struct {
 	XMLName xml.Name `xml:"author"`
 
-	Name string `xml:"name,omitempty"`
-	URI  string `xml:"uri,omitempty"`
+	Name  string `xml:"name,omitempty"`
+	URI   string `xml:"uri,omitempty"`
+	Email string `xml:"email,omitempty"`
+}
+
+// Contributor represents a contributor in the Atom feed
+type Contributor struct {
+	XMLName xml.Name `xml:"contributor"`
+
+	Name  string `xml:"name,omitempty"`
+	URI   string `xml:"uri,omitempty"`
+	Email string `xml:"email,omitempty"`
 }
 
-// EntryContent represents the content of a Entry in the Atom feed
+// Category represents a category of a feed or entry in the Atom feed
+type Category struct {
+	XMLName xml.Name `xml:"category"`
+
+	Term   string `xml:"term,attr"`
+	Scheme string `xml:"scheme,attr,omitempty"`
+	Label  string `xml:"label,attr,omitempty"`
+}
+
+// EntryContent represents the content of a Entry in the Atom feed. It's marshalled following RFC 4287 4.1.3,
+// see MarshalXML for how the Content is outputted for each Type.
 type EntryContent struct {
 	XMLName xml.Name `xml:"content"`
 
+	// Content is text for the text, html and text/* types, the markup inside the div for xhtml,
+	// a single element for the XML media types and base64 encoded data for the other media types
 	Content string `xml:",cdata"`
-	Type    string `xml:"type,attr,omitempty"`
+	// Type is text, html, xhtml or a MIME type, which is required when Src is set
+	Type string `xml:"type,attr,omitempty"`
+	// Src is the IRI of out-of-line content, where Content must be empty
+	Src string `xml:"src,attr,omitempty"`
+}
+
+// Archive represents the fh:archive marker of an archive feed document (RFC 5005)
+type Archive struct {
+	XMLName xml.Name `xml:"fh:archive"`
 }
 
 // Link represents a link in the Atom feed
 type Link struct {
 	XMLName xml.Name `xml:"link"`
 
-	Rel  string `xml:"rel,attr,omitempty"`
-	Type string `xml:"type,attr,omitempty"`
-	Href string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Href   string `xml:"href,attr"`
+	Length int64  `xml:"length,attr,omitempty"`
+
+	// ThreadCount and ThreadUpdated are the number of replies and the last time they were updated,
+	// for rel="replies" links (RFC 4685)
+	ThreadCount   *int       `xml:"thr:count,attr,omitempty"`
+	ThreadUpdated *time.Time `xml:"thr:updated,attr,omitempty"`
 }
 
 // Renderer is a "main struct", which generates a Feed
@@ -88,32 +234,61 @@ func NewRenderer(settings *Settings) *Renderer {
 	return &Renderer{settings}
 }
 
-// Author returns the Author of the feed
+// Author returns the Author of the feed, nil if there is no Settings.AuthorName
 func (a *Renderer) Author() *Author {
+	if a.Settings.AuthorName == "" {
+		return nil
+	}
 	return &Author{Name: a.Settings.AuthorName, URI: a.Settings.AuthorURIDefaulted()}
 }
 
+// Authors returns the Author of the feed in a slice, empty if there is no Author
+func (a *Renderer) Authors() []*Author {
+	author := a.Author()
+	if author == nil {
+		return nil
+	}
+	return []*Author{author}
+}
+
 // AlternateLink returns the HTML page variation of the feed
 func (a *Renderer) AlternateLink(url string) *Link {
 	return &Link{Rel: "alternate", Type: "text/html", Href: a.Settings.FullURLFor(url)}
 }
 
-// NewFeed returns a new Feed, with lots of defaults set
+// Title returns the title of the feed with the given feedName, see FeedOptions.Title
+func (a *Renderer) Title(feedName string) string {
+	return a.Settings.FeedOptions.Title(feedName, a.Settings.Host)
+}
+
+// NewFeed returns a new Feed, with lots of defaults set and the Settings.FeedOptions
 func (a *Renderer) NewFeed(feedName string, lastUpdated time.Time, selfURL, iconURL string) *Feed {
-	return &Feed{
-		XMLLang: "en-US",
-		XMLNS:   "http://www.w3.org/2005/Atom",
+	return a.NewFeedWithOptions(feedName, lastUpdated, selfURL, iconURL, a.Settings.FeedOptions)
+}
 
-		Title:   fmt.Sprintf("%v - %v", strings.Title(feedName), a.Settings.Host),
+// NewFeedWithOptions returns a new Feed like NewFeed, with the given options instead of the Settings.FeedOptions
+func (a *Renderer) NewFeedWithOptions(feedName string, lastUpdated time.Time, selfURL, iconURL string, options *FeedOptions) *Feed {
+	feed := &Feed{
+		XMLNS: Namespace,
+
+		Title:   options.Title(feedName, a.Settings.Host),
 		Icon:    a.Settings.FullURLFor(iconURL),
-		ID:      strings.Join([]string{a.Settings.Host, "2018", feedName}, ":"),
+		ID:      a.Settings.FeedID(feedName),
 		Updated: lastUpdated,
 
-		Author: a.Author(),
+		Authors: a.Authors(),
 
 		Links: []*Link{
-			{Rel: "self", Type: "application/atom+xml", Href: a.Settings.FullURLFor(selfURL)},
+			{Rel: "self", Type: AtomMediaType, Href: a.Settings.FullURLFor(selfURL)},
 			a.AlternateLink(""),
 		},
 	}
+	feed.setOptions(a.Settings, options)
+	for _, hub := range a.Settings.Hubs {
+		feed.Links = append(feed.Links, &Link{Rel: "hub", Href: hub})
+	}
+	if podcast := a.Podcast(); podcast != nil {
+		feed.SetPodcast(podcast)
+	}
+	return feed
 }
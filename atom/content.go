@@ -0,0 +1,81 @@
+package atom
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// urlAttributes are the HTML attributes that contain URLs
+var urlAttributes = map[string]bool{
+	"href":   true,
+	"src":    true,
+	"poster": true,
+}
+
+// AbsoluteURLs returns the htmlContent with the relative URLs in the href, src, poster and srcset
+// attributes resolved against baseURL. Everything else in htmlContent is kept as is.
+func AbsoluteURLs(htmlContent, baseURL string) (string, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+
+	return rewriteTags(htmlContent, func(token *html.Token) {
+		for i, attr := range token.Attr {
+			switch {
+			case urlAttributes[attr.Key]:
+				token.Attr[i].Val = resolveURL(base, attr.Val)
+			case attr.Key == "srcset":
+				token.Attr[i].Val = resolveSrcset(base, attr.Val)
+			}
+		}
+	})
+}
+
+func resolveURL(base *url.URL, rawURL string) string {
+	ref, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return rawURL
+	}
+	return base.ResolveReference(ref).String()
+}
+
+func resolveSrcset(base *url.URL, srcset string) string {
+	candidates := strings.Split(srcset, ",")
+	for i, candidate := range candidates {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+		fields[0] = resolveURL(base, fields[0])
+		candidates[i] = strings.Join(fields, " ")
+	}
+	return strings.Join(candidates, ", ")
+}
+
+// rewriteTags calls rewrite on every start tag of htmlContent, outputting the tag with the changes.
+// Everything else is outputted as it was.
+func rewriteTags(htmlContent string, rewrite func(token *html.Token)) (string, error) {
+	buffer := &bytes.Buffer{}
+	tokenizer := html.NewTokenizer(strings.NewReader(htmlContent))
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			if tokenizer.Err() == io.EOF {
+				return buffer.String(), nil
+			}
+			return "", tokenizer.Err()
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			rewrite(&token)
+			buffer.WriteString(token.String())
+		default:
+			buffer.Write(tokenizer.Raw())
+		}
+	}
+}
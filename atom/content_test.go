@@ -0,0 +1,79 @@
+package atom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func TestAbsoluteURLs(t *testing.T) {
+	testCases := []struct {
+		htmlContent string
+		exp         string
+	}{
+		{`<p>No links &amp; stuff</p>`, `<p>No links &amp; stuff</p>`},
+		{`<a href="/about">About</a>`, `<a href="https://yourwebsite.com/about">About</a>`},
+		{`<a href="second">Second</a>`, `<a href="https://yourwebsite.com/posts/second">Second</a>`},
+		{`<a href="#footnote">1</a>`, `<a href="https://yourwebsite.com/posts/first#footnote">1</a>`},
+		{`<a href="https://other.com/x">x</a>`, `<a href="https://other.com/x">x</a>`},
+		{`<a href="mailto:me@yourwebsite.com">me</a>`, `<a href="mailto:me@yourwebsite.com">me</a>`},
+		{`<img src="../images/a.png" alt="A &amp; B"/>`, `<img src="https://yourwebsite.com/images/a.png" alt="A &amp; B"/>`},
+		{`<img srcset="a.png 1x, /b.png 2x">`, `<img srcset="https://yourwebsite.com/posts/a.png 1x, https://yourwebsite.com/b.png 2x">`},
+		{`<video poster="p.png"><source src="v.mp4"></video>`, `<video poster="https://yourwebsite.com/posts/p.png"><source src="https://yourwebsite.com/posts/v.mp4"></video>`},
+		{`<pre><code>&lt;a href="x"&gt;</code></pre>`, `<pre><code>&lt;a href="x"&gt;</code></pre>`},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":       testCaseIndex,
+			"htmlContent": tc.htmlContent,
+		})
+
+		got, err := AbsoluteURLs(tc.htmlContent, "https://yourwebsite.com/posts/first")
+		if err != nil {
+			t.Error(context.String(err))
+		}
+		if got != tc.exp {
+			t.Error(context.GotExpString("Result", got, tc.exp))
+		}
+	}
+}
+
+func TestHTMLRenderer_Render_AbsoluteURLs(t *testing.T) {
+	htmlEntries := defaultHTMLEntries()[0:1]
+	htmlEntries[0].HTMLContent = `<img src="first.png">`
+
+	testCases := []struct {
+		absoluteURLs bool
+		exp          string
+	}{
+		{false, `<img src="first.png">`},
+		{true, `<img src="https://yourwebsite.com/first.png">`},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":        testCaseIndex,
+			"absoluteURLs": tc.absoluteURLs,
+		})
+
+		renderer := defaultHTMLRenderer()
+		renderer.AbsoluteURLs = tc.absoluteURLs
+		bytes, err := renderer.Render("posts", "posts.atom", "logo.png", htmlEntries)
+		if err != nil {
+			t.Error(context.String(err))
+		}
+
+		feed, err := Parse(strings.NewReader(string(bytes)))
+		if err != nil {
+			t.Error(context.String(err))
+			continue
+		}
+		got := feed.Entries[0].Content.Content
+		if got != tc.exp {
+			t.Error(context.GotExpString("Content", got, tc.exp))
+		}
+	}
+	test.AssertLabel(t, "htmlEntries[0].HTMLContent", htmlEntries[0].HTMLContent, `<img src="first.png">`)
+}
@@ -0,0 +1,81 @@
+package atom
+
+import (
+	"fmt"
+	"html/template"
+	"path"
+	"strings"
+)
+
+// DiscoveryFeed is a feed advertised in HTML pages by Discovery
+type DiscoveryFeed struct {
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
+	// Type is the media type of the feed, defaults to MediaType(URL)
+	Type string `json:"type,omitempty"`
+}
+
+// MediaType returns the media type of the feed at url from its extension: .rss and .json are RSSMediaType and
+// JSONFeedMediaType, everything else is AtomMediaType
+func MediaType(url string) string {
+	switch path.Ext(url) {
+	case ".rss":
+		return RSSMediaType
+	case ".json":
+		return JSONFeedMediaType
+	}
+	return AtomMediaType
+}
+
+// Discovery is a github.com/s12chung/gostatic/go/lib/router/html.Plugin, which outputs the
+// <link rel="alternate"> tags that let browsers and feed readers find the feeds of a page
+type Discovery struct {
+	Settings *Settings
+}
+
+// NewDiscovery returns a new instance of Discovery
+func NewDiscovery(settings *Settings) *Discovery {
+	return &Discovery{settings}
+}
+
+// FeedLink returns the <link rel="alternate"> tag of the feed at url with the given title, see MediaType
+func (discovery *Discovery) FeedLink(url, title string) template.HTML {
+	return discovery.feedLink(&DiscoveryFeed{URL: url, Title: title})
+}
+
+// TagFeedLink returns the <link rel="alternate"> tag of the feed of the given tag, see TagURL
+func (discovery *Discovery) TagFeedLink(selfURL, tag, title string) template.HTML {
+	return discovery.FeedLink(TagURL(selfURL, tag), title)
+}
+
+// FeedLinks returns the <link rel="alternate"> tags of Settings.DiscoveryFeeds, one per line
+func (discovery *Discovery) FeedLinks() template.HTML {
+	links := make([]string, len(discovery.Settings.DiscoveryFeeds))
+	for i, feed := range discovery.Settings.DiscoveryFeeds {
+		links[i] = string(discovery.feedLink(feed))
+	}
+	return template.HTML(strings.Join(links, "\n"))
+}
+
+func (discovery *Discovery) feedLink(feed *DiscoveryFeed) template.HTML {
+	mediaType := feed.Type
+	if mediaType == "" {
+		mediaType = MediaType(feed.URL)
+	}
+
+	title := ""
+	if feed.Title != "" {
+		title = fmt.Sprintf(` title="%v"`, template.HTMLEscapeString(feed.Title))
+	}
+	href := template.HTMLEscapeString(discovery.Settings.FullURLFor(feed.URL))
+	return template.HTML(fmt.Sprintf(`<link rel="alternate" type="%v"%v href="%v">`, template.HTMLEscapeString(mediaType), title, href))
+}
+
+// TemplateFuncs is the list of functions provided to the HTML templates
+func (discovery *Discovery) TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"feedLinks":   discovery.FeedLinks,
+		"feedLink":    discovery.FeedLink,
+		"tagFeedLink": discovery.TagFeedLink,
+	}
+}
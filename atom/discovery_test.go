@@ -0,0 +1,102 @@
+package atom
+
+import (
+	"bytes"
+	"html/template"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func defaultDiscovery() *Discovery {
+	settings := DefaultSettings()
+	settings.DiscoveryFeeds = []*DiscoveryFeed{
+		{URL: "posts.atom", Title: "Posts"},
+		{URL: "/posts.rss", Title: "Posts (RSS)"},
+		{URL: "posts.json", Title: `"Posts" & JSON`},
+		{URL: "feed.xml", Type: RSSMediaType},
+	}
+	return NewDiscovery(settings)
+}
+
+func TestMediaType(t *testing.T) {
+	testCases := []struct {
+		url string
+		exp string
+	}{
+		{"posts.atom", AtomMediaType},
+		{"/tags/go.rss", RSSMediaType},
+		{"posts.json", JSONFeedMediaType},
+		{"feed.xml", AtomMediaType},
+		{"posts", AtomMediaType},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+			"url":   tc.url,
+		})
+
+		got := MediaType(tc.url)
+		if got != tc.exp {
+			t.Error(context.GotExpString("Result", got, tc.exp))
+		}
+	}
+}
+
+func TestDiscovery_FeedLink(t *testing.T) {
+	testCases := []struct {
+		url   string
+		title string
+		exp   template.HTML
+	}{
+		{"posts.atom", "Posts", `<link rel="alternate" type="application/atom+xml" title="Posts" href="https://yourwebsite.com/posts.atom">`},
+		{"/posts.rss", "", `<link rel="alternate" type="application/rss+xml" href="https://yourwebsite.com/posts.rss">`},
+		{"posts.json", "<b>", `<link rel="alternate" type="application/feed+json" title="&lt;b&gt;" href="https://yourwebsite.com/posts.json">`},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+			"url":   tc.url,
+			"title": tc.title,
+		})
+
+		got := defaultDiscovery().FeedLink(tc.url, tc.title)
+		if got != tc.exp {
+			t.Error(context.GotExpString("Result", got, tc.exp))
+		}
+	}
+}
+
+func TestDiscovery_TemplateFuncs(t *testing.T) {
+	tmpl, err := template.New("head").Funcs(defaultDiscovery().TemplateFuncs()).Parse(
+		`<head>
+{{ feedLinks }}
+{{ feedLink "/posts/archive/1.atom" "Archive" }}
+{{ tagFeedLink "/posts.atom" "go lang" "Posts tagged go lang" }}
+</head>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := &bytes.Buffer{}
+	err = tmpl.Execute(buffer, nil)
+	if err != nil {
+		t.Error(err)
+	}
+
+	got := buffer.String()
+	fixtureFilename := "discovery.html"
+	if *updateFixturesPtr {
+		test.WriteFixture(t, fixtureFilename, []byte(got))
+		return
+	}
+
+	exp := string(test.ReadFixture(t, fixtureFilename))
+	if got != exp {
+		t.Error(test.NewContext().DiffString("template.Execute", got, exp, cmp.Diff(got, exp)))
+	}
+}
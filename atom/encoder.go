@@ -0,0 +1,121 @@
+package atom
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const feedEndTag = "</feed>"
+
+// attributeEscaper escapes the values of the pseudo-attributes in processing instructions
+var attributeEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+// Encoder writes the Atom xml data of a Feed to an io.Writer one Entry at a time,
+// so only a single Entry is in memory at a time. The output is the same as Feed.Marhshall.
+type Encoder struct {
+	writer io.Writer
+	gzip   *gzip.Writer
+
+	entries    *xml.Encoder
+	entryCount int
+}
+
+// NewEncoder returns a new Encoder writing to writer
+func NewEncoder(writer io.Writer) *Encoder {
+	return &Encoder{writer: writer}
+}
+
+// NewGzipEncoder returns a new Encoder writing gzip compressed data to writer
+func NewGzipEncoder(writer io.Writer) *Encoder {
+	gzipWriter := gzip.NewWriter(writer)
+	return &Encoder{writer: gzipWriter, gzip: gzipWriter}
+}
+
+// Encode writes the entire feed and closes the Encoder
+func (encoder *Encoder) Encode(feed *Feed) error {
+	err := encoder.Start(feed)
+	if err != nil {
+		return err
+	}
+	for _, entry := range feed.Entries {
+		err = encoder.EncodeEntry(entry)
+		if err != nil {
+			return err
+		}
+	}
+	return encoder.Close()
+}
+
+// Start writes the xml header and everything in the feed, except for Feed.Entries.
+// It must be called once before EncodeEntry. The namespaces of the Extensions of the feed
+// and the namespaced elements of its Feed.Entries are declared, see Feed.DeclareExtensions for other entries.
+func (encoder *Encoder) Start(feed *Feed) error {
+	if encoder.entries != nil {
+		return fmt.Errorf("atom.Encoder already started")
+	}
+
+	metadata := *feed
+	metadata.declareNamespaces()
+	metadata.Entries = nil
+	data, err := xml.MarshalIndent(&metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = bytes.TrimSuffix(data, []byte(feedEndTag))
+
+	_, err = io.WriteString(encoder.writer, xml.Header)
+	if err != nil {
+		return err
+	}
+	if feed.Stylesheet != "" {
+		_, err = fmt.Fprintf(encoder.writer, "<?xml-stylesheet type=\"text/xsl\" href=\"%v\"?>\n", attributeEscaper.Replace(feed.Stylesheet))
+		if err != nil {
+			return err
+		}
+	}
+	_, err = encoder.writer.Write(data)
+	if err != nil {
+		return err
+	}
+
+	encoder.entries = xml.NewEncoder(encoder.writer)
+	encoder.entries.Indent("  ", "  ")
+	return nil
+}
+
+// EncodeEntry writes the entry as the next entry of the feed
+func (encoder *Encoder) EncodeEntry(entry *Entry) error {
+	if encoder.entries == nil {
+		return fmt.Errorf("atom.Encoder.Start not called before EncodeEntry")
+	}
+	err := encoder.entries.Encode(entry)
+	if err != nil {
+		return err
+	}
+	encoder.entryCount++
+	return encoder.entries.Flush()
+}
+
+// Close writes the end of the feed and flushes the gzip data, if any. It does not close the underlying io.Writer.
+func (encoder *Encoder) Close() error {
+	if encoder.entries == nil {
+		return fmt.Errorf("atom.Encoder.Start not called before Close")
+	}
+
+	end := feedEndTag
+	if encoder.entryCount > 0 {
+		end = "\n" + end
+	}
+	_, err := io.WriteString(encoder.writer, end)
+	if err != nil {
+		return err
+	}
+	if encoder.gzip != nil {
+		return encoder.gzip.Close()
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package atom
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func TestEncoder_Encode(t *testing.T) {
+	htmlEntries := defaultHTMLEntries()
+	atomRenderer := NewRenderer(defaultHTMLRenderer().Settings)
+
+	testCases := []struct {
+		htmlEntries []*HTMLEntry
+	}{
+		{htmlEntries},
+		{htmlEntries[0:1]},
+		{[]*HTMLEntry{}},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+		})
+
+		feed := HTMLEntriesToFeed(atomRenderer, "posts", "posts.atom", "logo.png", tc.htmlEntries)
+		data, err := xml.MarshalIndent(feed, "", "  ")
+		if err != nil {
+			t.Error(context.String(err))
+		}
+		exp := xml.Header + string(data)
+
+		buffer := &bytes.Buffer{}
+		err = NewEncoder(buffer).Encode(feed)
+		if err != nil {
+			t.Error(context.String(err))
+		}
+		got := buffer.String()
+		if got != exp {
+			t.Error(context.DiffString("Encoder.Encode", got, exp, cmp.Diff(got, exp)))
+		}
+
+		buffer = &bytes.Buffer{}
+		err = NewGzipEncoder(buffer).Encode(feed)
+		if err != nil {
+			t.Error(context.String(err))
+		}
+		reader, err := gzip.NewReader(buffer)
+		if err != nil {
+			t.Error(context.String(err))
+			continue
+		}
+		uncompressed, err := ioutil.ReadAll(reader)
+		if err != nil {
+			t.Error(context.String(err))
+		}
+		got = string(uncompressed)
+		if got != exp {
+			t.Error(context.DiffString("NewGzipEncoder(...).Encode", got, exp, cmp.Diff(got, exp)))
+		}
+	}
+}
+
+func TestEncoder_NotStarted(t *testing.T) {
+	encoder := NewEncoder(&bytes.Buffer{})
+	if encoder.EncodeEntry(&Entry{}) == nil {
+		t.Error("EncodeEntry did not return an error before Start")
+	}
+	if encoder.Close() == nil {
+		t.Error("Close did not return an error before Start")
+	}
+
+	err := encoder.Start(&Feed{})
+	if err != nil {
+		t.Error(err)
+	}
+	if encoder.Start(&Feed{}) == nil {
+		t.Error("Start did not return an error when called twice")
+	}
+}
+
+func TestHtmlRenderer_RenderTo(t *testing.T) {
+	htmlEntries := defaultHTMLEntries()
+	htmlEntries[0].HTMLContent = `<p>The story <a href="/start">starts</a> here<script>alert("hi")</script></p>`
+
+	renderer := defaultHTMLRenderer()
+	renderer.AbsoluteURLs = true
+	renderer.Policy = DefaultPolicy()
+
+	for testCaseIndex, entries := range [][]*HTMLEntry{htmlEntries, {}} {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+		})
+
+		data, err := renderer.Render("posts", "posts.atom", "logo.png", entries)
+		if err != nil {
+			t.Error(context.String(err))
+		}
+		exp := string(data)
+
+		buffer := &bytes.Buffer{}
+		err = renderer.RenderTo(buffer, "posts", "posts.atom", "logo.png", entries)
+		if err != nil {
+			t.Error(context.String(err))
+		}
+		got := buffer.String()
+		if got != exp {
+			t.Error(context.DiffString("HTMLRenderer.RenderTo", got, exp, cmp.Diff(got, exp)))
+		}
+	}
+}
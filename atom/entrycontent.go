@@ -0,0 +1,459 @@
+package atom
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"golang.org/x/net/html"
+	htmlatom "golang.org/x/net/html/atom"
+)
+
+// XHTMLNamespace is the XML namespace of the div wrapping xhtml EntryContent
+const XHTMLNamespace = "http://www.w3.org/1999/xhtml"
+
+// contentKind is how the Content of an EntryContent is in the xml data (RFC 4287 4.1.3.3)
+type contentKind int
+
+const (
+	// textContent is escaped text, of the text, html and text/* types
+	textContent contentKind = iota
+	// xhtmlContent is the markup inside a XHTML div
+	xhtmlContent
+	// xmlContent is a single element, of the XML media types
+	xmlContent
+	// base64Content is base64 encoded data, of all other media types
+	base64Content
+	// outOfLineContent is empty, the content is at the src IRI
+	outOfLineContent
+)
+
+// entryContentXML is the xml data of an EntryContent, only one of CDATA, CharData or InnerXML is set
+type entryContentXML struct {
+	Type     string `xml:"type,attr,omitempty"`
+	Src      string `xml:"src,attr,omitempty"`
+	CDATA    string `xml:",cdata"`
+	CharData string `xml:",chardata"`
+	InnerXML string `xml:",innerxml"`
+}
+
+// NewXHTMLContent returns the htmlContent as xhtml EntryContent, see HTMLToXHTML
+func NewXHTMLContent(htmlContent string) (*EntryContent, error) {
+	xhtml, err := HTMLToXHTML(htmlContent)
+	if err != nil {
+		return nil, err
+	}
+	return &EntryContent{Content: xhtml, Type: "xhtml"}, nil
+}
+
+// NewBase64Content returns EntryContent with the data base64 encoded, for media types that aren't text or XML
+func NewBase64Content(mediaType string, data []byte) *EntryContent {
+	return &EntryContent{Content: base64.StdEncoding.EncodeToString(data), Type: mediaType}
+}
+
+// NewOutOfLineContent returns empty EntryContent of the mediaType, where the content is at the src IRI
+func NewOutOfLineContent(mediaType, src string) *EntryContent {
+	return &EntryContent{Type: mediaType, Src: src}
+}
+
+// Data returns the decoded data of base64 content, or the Content as is for other content
+func (content *EntryContent) Data() ([]byte, error) {
+	kind, err := content.kind()
+	if err != nil {
+		return nil, err
+	}
+	if kind != base64Content {
+		return []byte(content.Content), nil
+	}
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(content.Content), ""))
+}
+
+// MarshalXML marshalls the content as RFC 4287 4.1.3 describes, returning an error if the content breaks its rules
+func (content *EntryContent) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	data, err := content.xmlData()
+	if err != nil {
+		return err
+	}
+	start.Name = xml.Name{Local: "content"}
+	return encoder.EncodeElement(data, start)
+}
+
+func (content *EntryContent) xmlData() (*entryContentXML, error) {
+	kind, err := content.kind()
+	if err != nil {
+		return nil, fmt.Errorf("atom: content: %v", err)
+	}
+
+	data := &entryContentXML{Type: content.Type, Src: content.Src}
+	switch kind {
+	case textContent:
+		data.CDATA = content.Content
+	case xhtmlContent:
+		data.InnerXML, err = canonicalXML(content.Content, xml.HTMLEntity, false)
+		data.InnerXML = fmt.Sprintf(`<div xmlns="%v">%v</div>`, XHTMLNamespace, data.InnerXML)
+	case xmlContent:
+		data.InnerXML, err = canonicalXML(content.Content, nil, true)
+	case base64Content:
+		_, err = content.Data()
+		data.CharData = content.Content
+	}
+	if err != nil {
+		return nil, fmt.Errorf("atom: content of type %q: %v", content.Type, err)
+	}
+	return data, nil
+}
+
+// kind returns the contentKind of the content, or an error if it breaks the rules of RFC 4287 4.1.3
+func (content *EntryContent) kind() (contentKind, error) {
+	if content.Src != "" {
+		return content.outOfLineKind()
+	}
+
+	switch content.Type {
+	case "", "text", "html":
+		return textContent, nil
+	case "xhtml":
+		return xhtmlContent, nil
+	}
+	mediaType, err := contentMediaType(content.Type)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case strings.HasSuffix(mediaType, "+xml") || strings.HasSuffix(mediaType, "/xml"):
+		return xmlContent, nil
+	case strings.HasPrefix(mediaType, "text/"):
+		return textContent, nil
+	}
+	return base64Content, nil
+}
+
+func (content *EntryContent) outOfLineKind() (contentKind, error) {
+	if content.Content != "" {
+		return 0, fmt.Errorf("must be empty when src is set")
+	}
+	if content.Type == "" {
+		return outOfLineContent, nil
+	}
+	_, err := contentMediaType(content.Type)
+	if err != nil {
+		return 0, fmt.Errorf("type %q must be a MIME type when src is set", content.Type)
+	}
+	return outOfLineContent, nil
+}
+
+// contentMediaType returns the media type of the contentType, or an error if it's not a MIME type allowed in content
+func contentMediaType(contentType string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.Contains(mediaType, "/") {
+		return "", fmt.Errorf("type %q is not text, html, xhtml or a MIME type", contentType)
+	}
+	if strings.HasPrefix(mediaType, "multipart/") || strings.HasPrefix(mediaType, "message/") {
+		return "", fmt.Errorf("type %q is a composite MIME type", contentType)
+	}
+	return mediaType, nil
+}
+
+// UnmarshalXML unmarshalls the content as RFC 4287 4.1.3 describes. Content of an invalid type is read as text.
+func (content *EntryContent) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	content.XMLName = start.Name
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "type":
+			content.Type = attr.Value
+		case "src":
+			content.Src = attr.Value
+		}
+	}
+
+	tokens, err := elementTokens(decoder)
+	if err != nil {
+		return err
+	}
+	kind, err := content.kind()
+	if err != nil {
+		kind = textContent
+	}
+	switch kind {
+	case xhtmlContent:
+		content.Content, err = xhtmlDivContent(tokens)
+	case xmlContent:
+		content.Content, err = encodeTokens(trimSpaceTokens(tokens))
+	default:
+		content.Content, err = charData(tokens), nil
+	}
+	return err
+}
+
+// textConstruct is an Atom Text construct (RFC 4287 3.1), which is read like EntryContent of the text, html or xhtml type
+type textConstruct struct {
+	content EntryContent
+}
+
+// UnmarshalXML unmarshalls the Text construct like EntryContent.UnmarshalXML
+func (construct *textConstruct) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	return construct.content.UnmarshalXML(decoder, start)
+}
+
+// text returns the Text construct as plain text, "" for nil. The markup of html and xhtml is removed, see HTMLToText.
+func (construct *textConstruct) text() string {
+	if construct == nil {
+		return ""
+	}
+	switch construct.content.Type {
+	case "html", "xhtml":
+		return HTMLToText(construct.content.Content)
+	}
+	return construct.content.Content
+}
+
+// elementTokens returns the tokens of the decoder until the end of the current element
+func elementTokens(decoder *xml.Decoder) ([]xml.Token, error) {
+	var tokens []xml.Token
+	for depth := 0; ; {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch token.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				return tokens, nil
+			}
+			depth--
+		}
+		tokens = append(tokens, xml.CopyToken(token))
+	}
+}
+
+func charData(tokens []xml.Token) string {
+	buffer := &bytes.Buffer{}
+	for _, token := range tokens {
+		if data, ok := token.(xml.CharData); ok {
+			buffer.Write(data)
+		}
+	}
+	return buffer.String()
+}
+
+// xhtmlDivContent returns the markup inside the XHTML div of the tokens
+func xhtmlDivContent(tokens []xml.Token) (string, error) {
+	tokens = trimSpaceTokens(tokens)
+	elements, text := rootContent(tokens)
+	if elements != 1 || strings.TrimSpace(text) != "" {
+		return "", fmt.Errorf("atom: xhtml content is not a single XHTML div")
+	}
+	start, isStart := tokens[0].(xml.StartElement)
+	_, isEnd := tokens[len(tokens)-1].(xml.EndElement)
+	if !isStart || !isEnd || start.Name != (xml.Name{Space: XHTMLNamespace, Local: "div"}) {
+		return "", fmt.Errorf("atom: xhtml content is not a single XHTML div")
+	}
+	return encodeTokens(tokens[1 : len(tokens)-1])
+}
+
+func trimSpaceTokens(tokens []xml.Token) []xml.Token {
+	for len(tokens) > 0 && isSpace(tokens[0]) {
+		tokens = tokens[1:]
+	}
+	for len(tokens) > 0 && isSpace(tokens[len(tokens)-1]) {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return tokens
+}
+
+func isSpace(token xml.Token) bool {
+	data, ok := token.(xml.CharData)
+	return ok && len(bytes.TrimSpace(data)) == 0
+}
+
+// canonicalXML returns the markup as well-formed XML with the same escaping as encoding/xml,
+// or an error if it isn't well-formed. With singleElement, the markup must be a single element.
+func canonicalXML(markup string, entities map[string]string, singleElement bool) (string, error) {
+	tokens, err := rawTokens(markup, entities)
+	if err != nil {
+		return "", err
+	}
+	if singleElement {
+		tokens = trimSpaceTokens(tokens)
+		elements, text := rootContent(tokens)
+		if elements != 1 || strings.TrimSpace(text) != "" {
+			return "", fmt.Errorf("must be a single element")
+		}
+	}
+	return encodeTokens(tokens)
+}
+
+// rawTokens returns the tokens of the markup, with the prefixes in the local names, i.e. "svg:rect"
+func rawTokens(markup string, entities map[string]string) ([]xml.Token, error) {
+	decoder := xml.NewDecoder(strings.NewReader(markup))
+	decoder.Entity = entities
+
+	var tokens []xml.Token
+	var open []string
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			if len(open) > 0 {
+				return nil, fmt.Errorf("element <%v> is not closed", open[len(open)-1])
+			}
+			return tokens, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		token, open, err = prefixToken(token, open)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, xml.CopyToken(token))
+	}
+}
+
+// prefixToken moves the prefix of the names of the token to the local names, and checks that the token closes
+// the last open element if it's an end element
+func prefixToken(token xml.Token, open []string) (xml.Token, []string, error) {
+	switch t := token.(type) {
+	case xml.StartElement:
+		t.Name = prefixedName(t.Name)
+		attrs := make([]xml.Attr, len(t.Attr))
+		for i, attr := range t.Attr {
+			attrs[i] = xml.Attr{Name: prefixedName(attr.Name), Value: attr.Value}
+		}
+		t.Attr = attrs
+		return t, append(open, t.Name.Local), nil
+	case xml.EndElement:
+		t.Name = prefixedName(t.Name)
+		if len(open) == 0 || open[len(open)-1] != t.Name.Local {
+			return nil, nil, fmt.Errorf("unexpected end element </%v>", t.Name.Local)
+		}
+		return t, open[:len(open)-1], nil
+	}
+	return token, open, nil
+}
+
+func prefixedName(name xml.Name) xml.Name {
+	if name.Space == "" {
+		return name
+	}
+	return xml.Name{Local: name.Space + ":" + name.Local}
+}
+
+// rootContent returns the number of elements and the text that aren't in another element
+func rootContent(tokens []xml.Token) (int, string) {
+	elements := 0
+	text := &bytes.Buffer{}
+	depth := 0
+	for _, token := range tokens {
+		switch t := token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				elements++
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 {
+				text.Write(t)
+			}
+		}
+	}
+	return elements, text.String()
+}
+
+// encodeTokens returns the tokens encoded as XML, without processing instructions, directives
+// and the element names' namespaces
+func encodeTokens(tokens []xml.Token) (string, error) {
+	buffer := &bytes.Buffer{}
+	encoder := xml.NewEncoder(buffer)
+	for _, token := range tokens {
+		switch t := token.(type) {
+		case xml.ProcInst, xml.Directive:
+			continue
+		case xml.StartElement:
+			t.Name.Space = ""
+			token = t
+		case xml.EndElement:
+			t.Name.Space = ""
+			token = t
+		}
+		err := encoder.EncodeToken(token)
+		if err != nil {
+			return "", err
+		}
+	}
+	err := encoder.Flush()
+	if err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// HTMLToXHTML returns the htmlContent as XHTML, which is well-formed XML for xhtml EntryContent.
+// All text is escaped, including the text of raw text elements like <script> and <style>.
+func HTMLToXHTML(htmlContent string) (string, error) {
+	context := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: htmlatom.Div}
+	nodes, err := html.ParseFragment(strings.NewReader(htmlContent), context)
+	if err != nil {
+		return "", err
+	}
+
+	buffer := &bytes.Buffer{}
+	encoder := xml.NewEncoder(buffer)
+	for _, node := range nodes {
+		err = encodeXHTMLNode(encoder, node)
+		if err != nil {
+			return "", err
+		}
+	}
+	err = encoder.Flush()
+	if err != nil {
+		return "", err
+	}
+	return canonicalXML(buffer.String(), nil, false)
+}
+
+// encodeXHTMLNode encodes the HTML node and its children as XML tokens
+func encodeXHTMLNode(encoder *xml.Encoder, node *html.Node) error {
+	switch node.Type {
+	case html.TextNode:
+		return encoder.EncodeToken(xml.CharData(node.Data))
+	case html.CommentNode:
+		if strings.Contains(node.Data, "--") {
+			return nil
+		}
+		return encoder.EncodeToken(xml.Comment(node.Data))
+	case html.ElementNode:
+		return encodeXHTMLElement(encoder, node)
+	}
+	return nil
+}
+
+func encodeXHTMLElement(encoder *xml.Encoder, node *html.Node) error {
+	start := xml.StartElement{Name: xml.Name{Local: node.Data}}
+	for _, attr := range node.Attr {
+		name := attr.Key
+		if attr.Namespace != "" {
+			name = attr.Namespace + ":" + name
+		}
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: name}, Value: attr.Val})
+	}
+
+	err := encoder.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		err = encodeXHTMLNode(encoder, child)
+		if err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
@@ -0,0 +1,260 @@
+package atom
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func TestEntryContent_MarshalXML(t *testing.T) {
+	testCases := []struct {
+		content  *EntryContent
+		exp      string
+		expError bool
+	}{
+		{&EntryContent{Content: "<p>a</p>", Type: "html"}, `<content type="html"><![CDATA[<p>a</p>]]></content>`, false},
+		{&EntryContent{Content: "a < b"}, `<content><![CDATA[a < b]]></content>`, false},
+		{&EntryContent{Content: "a < b", Type: "text"}, `<content type="text"><![CDATA[a < b]]></content>`, false},
+		{&EntryContent{Content: "a < b", Type: "text/plain; charset=utf-8"}, `<content type="text/plain; charset=utf-8"><![CDATA[a < b]]></content>`, false},
+		{
+			&EntryContent{Content: `<p title='"q"'>a &amp; b&nbsp;<br/></p>`, Type: "xhtml"},
+			`<content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml"><p title="&#34;q&#34;">a &amp; b` + " " + `<br></br></p></div></content>`,
+			false,
+		},
+		{&EntryContent{Content: "<p>a<br></p>", Type: "xhtml"}, "", true},
+		{&EntryContent{Content: "<p>a</b>", Type: "xhtml"}, "", true},
+		{
+			&EntryContent{Content: ` <svg:svg xmlns:svg="http://www.w3.org/2000/svg"><svg:rect/></svg:svg> `, Type: "image/svg+xml"},
+			`<content type="image/svg+xml"><svg:svg xmlns:svg="http://www.w3.org/2000/svg"><svg:rect></svg:rect></svg:svg></content>`,
+			false,
+		},
+		{&EntryContent{Content: "<a></a><b></b>", Type: "application/xml"}, "", true},
+		{&EntryContent{Content: "text <a></a>", Type: "application/xml"}, "", true},
+		{NewBase64Content("image/png", []byte("png")), `<content type="image/png">cG5n</content>`, false},
+		{&EntryContent{Content: "not base64!", Type: "image/png"}, "", true},
+		{NewOutOfLineContent("video/mp4", "https://site.com/a.mp4"), `<content type="video/mp4" src="https://site.com/a.mp4"></content>`, false},
+		{&EntryContent{Src: "https://site.com/a"}, `<content src="https://site.com/a"></content>`, false},
+		{&EntryContent{Content: "a", Type: "video/mp4", Src: "https://site.com/a.mp4"}, "", true},
+		{&EntryContent{Type: "html", Src: "https://site.com/a.html"}, "", true},
+		{&EntryContent{Content: "a", Type: "markdown"}, "", true},
+		{&EntryContent{Content: "a", Type: "multipart/mixed"}, "", true},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+			"type":  tc.content.Type,
+		})
+
+		bytes, err := xml.Marshal(tc.content)
+		if (err != nil) != tc.expError {
+			t.Error(context.GotExpString("error", err, tc.expError))
+		}
+		got := string(bytes)
+		if got != tc.exp {
+			t.Error(context.GotExpString("Result", got, tc.exp))
+		}
+	}
+}
+
+func TestEntryContent_UnmarshalXML(t *testing.T) {
+	testCases := []struct {
+		data string
+		exp  *EntryContent
+	}{
+		{`<content type="html"><![CDATA[<p>a</p>]]></content>`, &EntryContent{Content: "<p>a</p>", Type: "html"}},
+		{`<content type="text">a &lt; b</content>`, &EntryContent{Content: "a < b", Type: "text"}},
+		{
+			`<content type="xhtml">
+  <div xmlns="http://www.w3.org/1999/xhtml"><p>a &amp; <b>b</b></p></div>
+</content>`,
+			&EntryContent{Content: "<p>a &amp; <b>b</b></p>", Type: "xhtml"},
+		},
+		{
+			`<content type="xhtml"><xhtml:div xmlns:xhtml="http://www.w3.org/1999/xhtml"><xhtml:p>a</xhtml:p></xhtml:div></content>`,
+			&EntryContent{Content: "<p>a</p>", Type: "xhtml"},
+		},
+		{
+			`<content type="image/svg+xml"> <svg xmlns="http://www.w3.org/2000/svg"><rect/></svg> </content>`,
+			&EntryContent{Content: `<svg xmlns="http://www.w3.org/2000/svg"><rect></rect></svg>`, Type: "image/svg+xml"},
+		},
+		{`<content type="image/png">cG5n</content>`, &EntryContent{Content: "cG5n", Type: "image/png"}},
+		{`<content type="video/mp4" src="https://site.com/a.mp4"/>`, &EntryContent{Type: "video/mp4", Src: "https://site.com/a.mp4"}},
+		{`<content type="markdown"># a</content>`, &EntryContent{Content: "# a", Type: "markdown"}},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+			"data":  tc.data,
+		})
+
+		got := &EntryContent{}
+		err := xml.Unmarshal([]byte(tc.data), got)
+		if err != nil {
+			t.Error(context.String(err))
+			continue
+		}
+		tc.exp.XMLName = xml.Name{Local: "content"}
+		if !cmp.Equal(got, tc.exp) {
+			t.Error(context.DiffString("Result", got, tc.exp, cmp.Diff(got, tc.exp)))
+		}
+	}
+
+	for _, data := range []string{
+		`<content type="xhtml"><p>a</p><p>b</p></content>`,
+		`<content type="xhtml"><div><p>a</p></div></content>`,
+	} {
+		err := xml.Unmarshal([]byte(data), &EntryContent{})
+		if err == nil {
+			t.Errorf("expected error for xhtml content without a XHTML div: %v", data)
+		}
+	}
+}
+
+func TestParse_XHTMLContent(t *testing.T) {
+	testCases := []struct {
+		div string
+		exp string
+	}{
+		{`<div xmlns="http://www.w3.org/1999/xhtml"><p>a <b>b</b></p></div>`, "<p>a <b>b</b></p>"},
+		{`<xhtml:div xmlns:xhtml="http://www.w3.org/1999/xhtml"><xhtml:p>a <xhtml:b>b</xhtml:b></xhtml:p></xhtml:div>`, "<p>a <b>b</b></p>"},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+			"div":   tc.div,
+		})
+
+		data := `<feed xmlns="http://www.w3.org/2005/Atom"><entry><id>a</id><content type="xhtml">` + tc.div + `</content></entry></feed>`
+		feed, err := Parse(strings.NewReader(data))
+		if err != nil {
+			t.Error(context.String(err))
+			continue
+		}
+		test.AssertLabel(t, context.String("Content"), feed.Entries[0].Content.Content, tc.exp)
+	}
+}
+
+func TestEntryContent_Data(t *testing.T) {
+	testCases := []struct {
+		content  *EntryContent
+		exp      string
+		expError bool
+	}{
+		{&EntryContent{Content: "<p>a</p>", Type: "html"}, "<p>a</p>", false},
+		{&EntryContent{Content: "cG5n", Type: "image/png"}, "png", false},
+		{&EntryContent{Content: "cG\n5n\n", Type: "image/png"}, "png", false},
+		{&EntryContent{Content: "!", Type: "image/png"}, "", true},
+		{&EntryContent{Content: "a", Type: "multipart/mixed"}, "", true},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+		})
+
+		data, err := tc.content.Data()
+		if (err != nil) != tc.expError {
+			t.Error(context.GotExpString("error", err, tc.expError))
+		}
+		test.AssertLabel(t, context.String("Result"), string(data), tc.exp)
+	}
+}
+
+func TestHTMLToXHTML(t *testing.T) {
+	testCases := []struct {
+		htmlContent string
+		exp         string
+	}{
+		{`<p>The story</p>`, `<p>The story</p>`},
+		{`<p>a<br>b &amp; c&nbsp;</p>`, "<p>a<br></br>b &amp; c </p>"},
+		{`<img src="a.png" alt='"A"'>`, `<img src="a.png" alt="&#34;A&#34;"></img>`},
+		{`<p>unclosed <b>bold`, `<p>unclosed <b>bold</b></p>`},
+		{`text only`, `text only`},
+		{`<script>if (a < b && c) { x("</p>") }</script>`, `<script>if (a &lt; b &amp;&amp; c) { x(&#34;&lt;/p&gt;&#34;) }</script>`},
+		{`<style>a > b { color: red }</style><!-- note -->`, `<style>a &gt; b { color: red }</style><!-- note -->`},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":       testCaseIndex,
+			"htmlContent": tc.htmlContent,
+		})
+
+		got, err := HTMLToXHTML(tc.htmlContent)
+		if err != nil {
+			t.Error(context.String(err))
+		}
+		if got != tc.exp {
+			t.Error(context.GotExpString("Result", got, tc.exp))
+		}
+	}
+}
+
+func contentHTMLEntries() []*HTMLEntry {
+	htmlEntries := defaultHTMLEntries()
+	htmlEntries[0].ContentType = "xhtml"
+	htmlEntries[0].HTMLContent = "<p>The story<br>starts &amp; here</p>"
+	htmlEntries[1].ContentType = "text"
+	htmlEntries[2].Content = NewOutOfLineContent("video/mp4", "third.mp4")
+	return htmlEntries
+}
+
+func TestHtmlRenderer_Render_ContentTypes(t *testing.T) {
+	bytes, err := defaultHTMLRenderer().Render("posts", "posts.atom", "logo.png", contentHTMLEntries())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(bytes)
+	fixtureFilename := "content.xml"
+	if *updateFixturesPtr {
+		test.WriteFixture(t, fixtureFilename, []byte(got))
+		return
+	}
+
+	exp := string(test.ReadFixture(t, fixtureFilename))
+	if got != exp {
+		t.Error(test.NewContext().DiffString("HTMLRenderer.Render", got, exp, cmp.Diff(got, exp)))
+	}
+	if !strings.Contains(got, `src="https://yourwebsite.com/third.mp4"`) {
+		t.Error("expected the full URL of the out-of-line content")
+	}
+}
+
+func TestHtmlRenderer_Render_InvalidXHTML(t *testing.T) {
+	testCases := []struct {
+		htmlContent string
+	}{
+		{`<p a"b="1">invalid attribute</p>`},
+		{`<button @click="go">Go</button>`},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":       testCaseIndex,
+			"htmlContent": tc.htmlContent,
+		})
+
+		htmlEntries := defaultHTMLEntries()
+		htmlEntries[1].ContentType = "xhtml"
+		htmlEntries[1].HTMLContent = tc.htmlContent
+
+		_, err := defaultHTMLRenderer().Render("posts", "posts.atom", "logo.png", htmlEntries)
+		if err == nil || !strings.Contains(err.Error(), "entry "+htmlEntries[1].ID+":") {
+			t.Error(context.GotExpString("error", err, "error of entry "+htmlEntries[1].ID))
+		}
+
+		entry := htmlEntries[1].ToEntry(defaultHTMLRenderer().atomRenderer())
+		exp := &EntryContent{Content: tc.htmlContent, Type: "html"}
+		if !cmp.Equal(entry.Content, exp) {
+			t.Error(context.DiffString("ToEntry Content", entry.Content, exp, cmp.Diff(entry.Content, exp)))
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package atom
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MediaNamespace is the XML namespace of Media RSS
+const MediaNamespace = "http://search.yahoo.com/mrss/"
+
+// GeoRSSNamespace is the XML namespace of GeoRSS-Simple
+const GeoRSSNamespace = "http://www.georss.org/georss"
+
+func init() {
+	RegisterExtension("thumbnail", func() Extension { return &MediaThumbnail{} })
+	RegisterExtension("point", func() Extension { return &GeoPoint{} })
+}
+
+// Extension is a namespaced extension element of a Feed or Entry, i.e. a GeoPoint.
+// The struct of an Extension has a XMLName with the "prefix:local" name of the element,
+// and a prefix matching ExtensionNamespace.
+type Extension interface {
+	// ExtensionNamespace returns the XML namespace of the element and the prefix it's declared with
+	ExtensionNamespace() (namespace, prefix string)
+}
+
+// Extensions are the extension elements of a Feed or Entry. Parse decodes the elements registered
+// with RegisterExtension into them and skips the others.
+type Extensions []Extension
+
+// extensionTypes maps the "prefix:local" names of the registered extension elements to their constructors
+var extensionTypes = map[string]func() Extension{}
+
+// RegisterExtension registers the extension element with the local name, which newExtension returns
+// a new instance of, so that Parse decodes it into Extensions. The namespace and prefix come from
+// the ExtensionNamespace of the instance.
+//
+// It's meant to be called in an init function, it's not safe to call concurrently with Parse.
+// It panics if the element or the prefix is already registered for another namespace.
+func RegisterExtension(local string, newExtension func() Extension) {
+	namespace, prefix := newExtension().ExtensionNamespace()
+	for registeredNamespace, registeredPrefix := range namespacePrefixes {
+		if registeredPrefix == prefix && registeredNamespace != namespace {
+			panic(fmt.Sprintf("atom: prefix %q is already registered for %v", prefix, registeredNamespace))
+		}
+	}
+
+	name := prefix + ":" + local
+	if _, contains := extensionTypes[name]; contains {
+		panic(fmt.Sprintf("atom: extension %v is already registered", name))
+	}
+	extensionTypes[name] = newExtension
+	namespacePrefixes[namespace] = prefix
+}
+
+// UnmarshalXML decodes the extension element into a new Extension, when it's registered
+func (extensions *Extensions) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	newExtension, contains := extensionTypes[start.Name.Local]
+	if !contains {
+		return decoder.Skip()
+	}
+
+	extension := newExtension()
+	err := decoder.DecodeElement(extension, &start)
+	if err != nil {
+		return err
+	}
+	*extensions = append(*extensions, extension)
+	return nil
+}
+
+// DeclareExtensions adds the xmlns attributes of the namespaces of the extensions that aren't declared yet.
+// Encoder.Start calls it for the Feed.Extensions and the Entry.Extensions of the Feed.Entries, so only
+// call it for the extensions of entries given to Encoder.EncodeEntry.
+func (feed *Feed) DeclareExtensions(extensions []Extension) {
+	declared := map[string]bool{}
+	for _, attr := range feed.XMLNSExtensions {
+		declared[attr.Name.Local] = true
+	}
+	for _, extension := range extensions {
+		namespace, prefix := extension.ExtensionNamespace()
+		name := "xmlns:" + prefix
+		if declared[name] {
+			continue
+		}
+		declared[name] = true
+		feed.XMLNSExtensions = append(feed.XMLNSExtensions, xml.Attr{Name: xml.Name{Local: name}, Value: namespace})
+	}
+}
+
+// declareNamespaces declares the namespaces of the extensions of the feed and the namespaced elements of its entries,
+// which may come from other feeds, see Aggregator
+func (feed *Feed) declareNamespaces() {
+	feed.XMLNSExtensions = append([]xml.Attr(nil), feed.XMLNSExtensions...)
+	feed.DeclareExtensions(feed.Extensions)
+	for _, entry := range feed.Entries {
+		feed.DeclareExtensions(entry.Extensions)
+		if entry.threaded() {
+			feed.XMLNSThr = ThreadNamespace
+		}
+		if entry.PodcastEpisode != nil {
+			feed.XMLNSITunes = ITunesNamespace
+		}
+	}
+}
+
+// MediaThumbnail represents the media:thumbnail image of a Feed or Entry (Media RSS)
+type MediaThumbnail struct {
+	XMLName xml.Name `xml:"media:thumbnail"`
+
+	URL    string `xml:"url,attr"`
+	Width  int    `xml:"width,attr,omitempty"`
+	Height int    `xml:"height,attr,omitempty"`
+	// Time is the NTP time offset of the thumbnail in the media, i.e. "12:05:01.123"
+	Time string `xml:"time,attr,omitempty"`
+}
+
+// ExtensionNamespace returns MediaNamespace and the "media" prefix
+func (thumbnail *MediaThumbnail) ExtensionNamespace() (string, string) {
+	return MediaNamespace, "media"
+}
+
+// GeoPoint represents the georss:point location of a Feed or Entry (GeoRSS-Simple)
+type GeoPoint struct {
+	XMLName xml.Name `xml:"georss:point"`
+
+	// Point is the latitude and longitude separated by a space, i.e. "45.256 -71.92"
+	Point string `xml:",chardata"`
+}
+
+// NewGeoPoint returns a new GeoPoint at the latitude and longitude
+func NewGeoPoint(latitude, longitude float64) *GeoPoint {
+	return &GeoPoint{Point: strconv.FormatFloat(latitude, 'f', -1, 64) + " " + strconv.FormatFloat(longitude, 'f', -1, 64)}
+}
+
+// ExtensionNamespace returns GeoRSSNamespace and the "georss" prefix
+func (point *GeoPoint) ExtensionNamespace() (string, string) {
+	return GeoRSSNamespace, "georss"
+}
+
+// LatLong returns the latitude and longitude of the point
+func (point *GeoPoint) LatLong() (float64, float64, error) {
+	fields := strings.Fields(point.Point)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("georss:point %q is not a latitude and longitude", point.Point)
+	}
+	latitude, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	longitude, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return latitude, longitude, nil
+}
@@ -0,0 +1,165 @@
+package atom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func extensionHTMLEntries() []*HTMLEntry {
+	htmlEntries := defaultHTMLEntries()
+	htmlEntries[0].Extensions = []Extension{NewGeoPoint(45.256, -71.92)}
+	htmlEntries[2].Extensions = []Extension{
+		&MediaThumbnail{URL: "https://yourwebsite.com/third.png", Width: 75, Height: 50},
+		NewGeoPoint(-33.8688, 151.2093),
+	}
+	return htmlEntries
+}
+
+func TestHtmlRenderer_Render_Extensions(t *testing.T) {
+	renderer := defaultHTMLRenderer()
+	bytes, err := renderer.Render("posts", "posts.atom", "logo.png", extensionHTMLEntries())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(bytes)
+	fixtureFilename := "extension.xml"
+	if *updateFixturesPtr {
+		test.WriteFixture(t, fixtureFilename, []byte(got))
+		return
+	}
+
+	exp := string(test.ReadFixture(t, fixtureFilename))
+	if got != exp {
+		t.Error(test.NewContext().DiffString("HTMLRenderer.Render", got, exp, cmp.Diff(got, exp)))
+	}
+
+	writer := &strings.Builder{}
+	err = renderer.RenderTo(writer, "posts", "posts.atom", "logo.png", extensionHTMLEntries())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = writer.String()
+	if got != exp {
+		t.Error(test.NewContext().DiffString("HTMLRenderer.RenderTo", got, exp, cmp.Diff(got, exp)))
+	}
+}
+
+func TestParse_Extensions(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:m="http://search.yahoo.com/mrss/" xmlns:unknown="https://unknown.com/ns">
+  <m:thumbnail url="https://site.com/feed.png"></m:thumbnail>
+  <unknown:thing>skipped</unknown:thing>
+  <entry>
+    <id>a</id>
+    <point xmlns="http://www.georss.org/georss">45.256 -71.92</point>
+    <unknown:thing>skipped</unknown:thing>
+  </entry>
+</feed>`
+	feed, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name string
+		got  Extensions
+		exp  Extensions
+	}{
+		{"Feed", feed.Extensions, Extensions{&MediaThumbnail{URL: "https://site.com/feed.png"}}},
+		{"Entry", feed.Entries[0].Extensions, Extensions{&GeoPoint{Point: "45.256 -71.92"}}},
+	}
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+			"name":  tc.name,
+		})
+		ignoreXMLName := cmp.Options{cmpopts.IgnoreFields(MediaThumbnail{}, "XMLName"), cmpopts.IgnoreFields(GeoPoint{}, "XMLName")}
+		if !cmp.Equal(tc.got, tc.exp, ignoreXMLName) {
+			t.Error(context.DiffString("Extensions", tc.got, tc.exp, cmp.Diff(tc.got, tc.exp, ignoreXMLName)))
+		}
+	}
+}
+
+func TestFeed_DeclareExtensions(t *testing.T) {
+	feed := &Feed{}
+	feed.DeclareExtensions([]Extension{NewGeoPoint(1, 2), &MediaThumbnail{}})
+	feed.DeclareExtensions([]Extension{NewGeoPoint(3, 4)})
+
+	buffer := &bytes.Buffer{}
+	err := NewEncoder(buffer).Start(feed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := buffer.String()
+	for _, exp := range []string{`xmlns:georss="` + GeoRSSNamespace + `"`, `xmlns:media="` + MediaNamespace + `"`} {
+		if strings.Count(got, exp) != 1 {
+			t.Errorf("expected %v once in: %v", exp, got)
+		}
+	}
+}
+
+func TestGeoPoint_LatLong(t *testing.T) {
+	testCases := []struct {
+		point        string
+		expLatitude  float64
+		expLongitude float64
+		expError     bool
+	}{
+		{"45.256 -71.92", 45.256, -71.92, false},
+		{" 0   180 ", 0, 180, false},
+		{"45.256", 0, 0, true},
+		{"north west", 0, 0, true},
+		{"45.256 west", 0, 0, true},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+			"point": tc.point,
+		})
+
+		latitude, longitude, err := (&GeoPoint{Point: tc.point}).LatLong()
+		if (err != nil) != tc.expError {
+			t.Error(context.GotExpString("error", err, tc.expError))
+		}
+		if latitude != tc.expLatitude || longitude != tc.expLongitude {
+			t.Error(context.GotExpString("LatLong", []float64{latitude, longitude}, []float64{tc.expLatitude, tc.expLongitude}))
+		}
+	}
+}
+
+func TestRegisterExtension_Panics(t *testing.T) {
+	testCases := []struct {
+		newExtension func() Extension
+	}{
+		{func() Extension { return &GeoPoint{} }},
+		{func() Extension { return &conflictingExtension{} }},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+		})
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error(context.String("expected panic"))
+				}
+			}()
+			RegisterExtension("point", tc.newExtension)
+		}()
+	}
+}
+
+type conflictingExtension struct{}
+
+func (extension *conflictingExtension) ExtensionNamespace() (string, string) {
+	return "https://other.com/ns", "georss"
+}
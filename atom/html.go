@@ -1,7 +1,8 @@
 package atom
 
 import (
-	"strings"
+	"fmt"
+	"io"
 	"time"
 )
 
@@ -16,41 +17,267 @@ type HTMLEntry struct {
 	HTMLContent string
 	Summary     string
 	Published   time.Time
+	Tags        []string
+	// ContentType is how HTMLContent is in the Atom feed: "html" (the default), "xhtml" (see HTMLToXHTML) or "text" (see HTMLToText).
+	// HTMLRenderer returns an error for content it can't convert to xhtml, while ToEntry falls back to "html".
+	ContentType string
+	// Content overrides HTMLContent in the Atom feed when set, i.e. for out-of-line or base64 content
+	Content *EntryContent
+	// Language is the xml:lang of the entry, when it's different from the language of the feed
+	Language string
+
+	// Authors overrides the Renderer.Author for the entry when set
+	Authors      []*Author
+	Contributors []*Contributor
+
+	Enclosures []*Enclosure
+	// InReplyTo is the HTMLEntry this entry replies to, i.e. the post of a comment
+	InReplyTo *HTMLEntry
+	// Replies adds a rel="replies" link to the feed of the replies to this entry, see NewReplies
+	Replies *Replies
+	// Episode is only outputted when Settings.Podcast is set
+	Episode *PodcastEpisode
+	// Extensions are the namespaced extension elements of the entry, i.e. a GeoPoint
+	Extensions []Extension
 }
 
 // ToEntry converts the HTMLEntry to an atom Entry
 func (htmlEntry *HTMLEntry) ToEntry(a *Renderer) *Entry {
 	return &Entry{
-		ID:      strings.Join([]string{a.Settings.Host, htmlEntry.ID, htmlEntry.Published.Format("2006-01-02")}, ":"),
+		XMLLang: htmlEntry.Language,
+		ID:      a.Settings.EntryID(htmlEntry.ID, htmlEntry.Published),
 		Title:   htmlEntry.Title,
 		Updated: htmlEntry.Updated,
 
-		Author:  a.Author(),
-		Content: &EntryContent{Content: htmlEntry.HTMLContent, Type: "html"},
-		Summary: htmlEntry.Summary,
-		Link:    a.AlternateLink(htmlEntry.ID),
+		Authors:      htmlEntry.authors(a),
+		Contributors: htmlEntry.Contributors,
+		Content:      htmlEntry.content(a),
+		Summary:      htmlEntry.Summary,
+		Links:        htmlEntry.links(a),
+		Categories:   tagsToCategories(htmlEntry.Tags),
+		InReplyTo:    htmlEntry.inReplyTo(a),
 
 		Published: htmlEntry.Published,
+
+		PodcastEpisode: htmlEntry.episode(a),
+
+		Extensions: htmlEntry.Extensions,
 	}
 }
 
+func (htmlEntry *HTMLEntry) content(a *Renderer) *EntryContent {
+	if htmlEntry.Content != nil {
+		content := *htmlEntry.Content
+		if content.Src != "" {
+			content.Src = a.Settings.FullURLFor(content.Src)
+		}
+		return &content
+	}
+
+	switch htmlEntry.ContentType {
+	case "text":
+		return &EntryContent{Content: HTMLToText(htmlEntry.HTMLContent), Type: "text"}
+	case "xhtml":
+		// HTMLRenderer reports the entries that can't be converted, see processEntry
+		content, err := NewXHTMLContent(htmlEntry.HTMLContent)
+		if err == nil {
+			return content
+		}
+	}
+	return &EntryContent{Content: htmlEntry.HTMLContent, Type: "html"}
+}
+
+func (htmlEntry *HTMLEntry) links(a *Renderer) []*Link {
+	links := []*Link{a.AlternateLink(htmlEntry.ID)}
+	for _, enclosure := range htmlEntry.Enclosures {
+		links = append(links, a.EnclosureLink(enclosure))
+	}
+	if htmlEntry.Replies != nil {
+		links = append(links, a.RepliesLink(htmlEntry.Replies))
+	}
+	return links
+}
+
+func (htmlEntry *HTMLEntry) episode(a *Renderer) *PodcastEpisode {
+	if a.Settings.Podcast == nil {
+		return nil
+	}
+	return htmlEntry.Episode
+}
+
+func (htmlEntry *HTMLEntry) authors(a *Renderer) []*Author {
+	if len(htmlEntry.Authors) > 0 {
+		return htmlEntry.Authors
+	}
+	return a.Authors()
+}
+
+// tagsToCategories returns the tags as categories, without empty and duplicate tags like TaggedHTMLEntries
+func tagsToCategories(tags []string) []*Category {
+	var categories []*Category
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		categories = append(categories, &Category{Term: tag})
+	}
+	return categories
+}
+
 // HTMLRenderer is a "main struct", which generates a Feed. It builds on top of Renderer.
 type HTMLRenderer struct {
 	Settings *Settings
+	// EntryLimit is the max number of entries per feed page for RenderPages
+	EntryLimit int
+	// AbsoluteURLs rewrites the relative URLs in HTMLEntry.HTMLContent against the entry's URL, see AbsoluteURLs
+	AbsoluteURLs bool
+	// Policy sanitizes HTMLEntry.HTMLContent when set, see DefaultPolicy
+	Policy *Policy
+	// SummaryLength is the max length of the summaries generated for entries without a HTMLEntry.Summary,
+	// see Summarize and the recommended SummaryLength. Summaries are not generated when it's <= 0, the default.
+	SummaryLength int
+	// Stylesheet is the href of the XSLT stylesheet of the feeds, see Feed.Stylesheet
+	Stylesheet string
+	// FeedOptions overrides the Settings.FeedOptions of the feeds when set
+	FeedOptions *FeedOptions
 }
 
 // NewHTMLRenderer returns a new instance of HTMLRenderer
 func NewHTMLRenderer(settings *Settings) *HTMLRenderer {
-	return &HTMLRenderer{settings}
+	return &HTMLRenderer{Settings: settings, EntryLimit: EntryLimit}
 }
 
 // Render  the Atom xml data a generated feed
 func (renderer *HTMLRenderer) Render(feedName, selfURL, logoURL string, htmlEntries []*HTMLEntry) ([]byte, error) {
-	atomRenderer := NewRenderer(renderer.Settings)
+	htmlEntries, err := renderer.processEntries(htmlEntries)
+	if err != nil {
+		return nil, err
+	}
+	atomRenderer := renderer.atomRenderer()
 	feed := HTMLEntriesToFeed(atomRenderer, feedName, selfURL, logoURL, htmlEntries)
+	return renderer.marshall(feed)
+}
+
+// atomRenderer returns the Renderer of the feeds, with the HTMLRenderer.FeedOptions
+func (renderer *HTMLRenderer) atomRenderer() *Renderer {
+	if renderer.FeedOptions == nil {
+		return NewRenderer(renderer.Settings)
+	}
+	settings := *renderer.Settings
+	settings.FeedOptions = renderer.FeedOptions
+	return NewRenderer(&settings)
+}
+
+// marshall returns the Atom xml data of the feed with the HTMLRenderer options applied to it
+func (renderer *HTMLRenderer) marshall(feed *Feed) ([]byte, error) {
+	feed.Stylesheet = renderer.Stylesheet
 	return feed.Marhshall()
 }
 
+// RenderTo writes the Atom xml data of a generated feed to writer, see Encode
+func (renderer *HTMLRenderer) RenderTo(writer io.Writer, feedName, selfURL, logoURL string, htmlEntries []*HTMLEntry) error {
+	return renderer.Encode(NewEncoder(writer), feedName, selfURL, logoURL, htmlEntries)
+}
+
+// Encode writes a generated feed with the encoder and closes it. Each HTMLEntry is processed and written
+// one at a time, so memory use doesn't grow with the content of htmlEntries.
+func (renderer *HTMLRenderer) Encode(encoder *Encoder, feedName, selfURL, logoURL string, htmlEntries []*HTMLEntry) error {
+	atomRenderer := renderer.atomRenderer()
+	feed := atomRenderer.NewFeed(feedName, LastUpdated(renderer.Settings, htmlEntries), selfURL, logoURL)
+	if threaded(htmlEntries) {
+		feed.XMLNSThr = ThreadNamespace
+	}
+	for _, htmlEntry := range htmlEntries {
+		feed.DeclareExtensions(htmlEntry.Extensions)
+	}
+	feed.Stylesheet = renderer.Stylesheet
+	err := encoder.Start(feed)
+	if err != nil {
+		return err
+	}
+
+	for _, htmlEntry := range htmlEntries {
+		htmlEntry, err = renderer.processEntry(htmlEntry)
+		if err != nil {
+			return err
+		}
+		err = encoder.EncodeEntry(htmlEntry.ToEntry(atomRenderer))
+		if err != nil {
+			return err
+		}
+	}
+	return encoder.Close()
+}
+
+// processEntries returns copies of htmlEntries with the HTMLRenderer options applied to them
+func (renderer *HTMLRenderer) processEntries(htmlEntries []*HTMLEntry) ([]*HTMLEntry, error) {
+	processed := make([]*HTMLEntry, len(htmlEntries))
+	for i, htmlEntry := range htmlEntries {
+		copied, err := renderer.processEntry(htmlEntry)
+		if err != nil {
+			return nil, err
+		}
+		processed[i] = copied
+	}
+	return processed, nil
+}
+
+// processEntry returns a copy of htmlEntry with the HTMLRenderer options applied to it
+func (renderer *HTMLRenderer) processEntry(htmlEntry *HTMLEntry) (*HTMLEntry, error) {
+	copied := *htmlEntry
+	if copied.Summary == "" && renderer.SummaryLength > 0 {
+		copied.Summary = Summarize(copied.HTMLContent, renderer.SummaryLength)
+	}
+	content, err := renderer.processContent(&copied)
+	if err != nil {
+		return nil, err
+	}
+	copied.HTMLContent = content
+	if copied.ContentType == "xhtml" && copied.Content == nil {
+		copied.Content, err = NewXHTMLContent(copied.HTMLContent)
+		if err != nil {
+			return nil, fmt.Errorf("entry %v: xhtml content: %v", copied.ID, err)
+		}
+	}
+	return &copied, nil
+}
+
+func (renderer *HTMLRenderer) processContent(htmlEntry *HTMLEntry) (string, error) {
+	content := htmlEntry.HTMLContent
+	var err error
+	if renderer.AbsoluteURLs {
+		content, err = AbsoluteURLs(content, renderer.Settings.FullURLFor(htmlEntry.ID))
+		if err != nil {
+			return "", err
+		}
+	}
+	if renderer.Policy != nil {
+		content, _, err = renderer.Policy.Sanitize(content)
+	}
+	return content, err
+}
+
+// SanitizeReport returns what the Policy removes from each HTMLEntry.HTMLContent, mapped by HTMLEntry.ID.
+// Entries with nothing removed are not in the map.
+func (renderer *HTMLRenderer) SanitizeReport(htmlEntries []*HTMLEntry) (map[string][]*Removal, error) {
+	report := map[string][]*Removal{}
+	if renderer.Policy == nil {
+		return report, nil
+	}
+	for _, htmlEntry := range htmlEntries {
+		_, removals, err := renderer.Policy.Sanitize(htmlEntry.HTMLContent)
+		if err != nil {
+			return nil, err
+		}
+		if len(removals) > 0 {
+			report[htmlEntry.ID] = removals
+		}
+	}
+	return report, nil
+}
+
 // HTMLEntriesToFeed converts []HTMLEntry to a Feed, with lots of defaults set
 func HTMLEntriesToFeed(atomRenderer *Renderer, feedName, selfURL, logoURL string, htmlEntries []*HTMLEntry) *Feed {
 	entries := make([]*Entry, len(htmlEntries))
@@ -58,11 +285,24 @@ func HTMLEntriesToFeed(atomRenderer *Renderer, feedName, selfURL, logoURL string
 		entries[i] = htmlEntry.ToEntry(atomRenderer)
 	}
 
-	lastUpdated := time.Now()
-	if len(htmlEntries) >= 1 {
-		lastUpdated = htmlEntries[0].Updated
+	feed := atomRenderer.NewFeed(feedName, LastUpdated(atomRenderer.Settings, htmlEntries), selfURL, logoURL)
+	if threaded(htmlEntries) {
+		feed.XMLNSThr = ThreadNamespace
 	}
-	feed := atomRenderer.NewFeed(feedName, lastUpdated, selfURL, logoURL)
 	feed.Entries = entries
 	return feed
 }
+
+// LastUpdated returns the latest HTMLEntry.Updated of the htmlEntries, or Settings.Now() if there are none
+func LastUpdated(settings *Settings, htmlEntries []*HTMLEntry) time.Time {
+	if len(htmlEntries) == 0 {
+		return settings.Now()
+	}
+	last := htmlEntries[0].Updated
+	for _, htmlEntry := range htmlEntries[1:] {
+		if htmlEntry.Updated.After(last) {
+			last = htmlEntry.Updated
+		}
+	}
+	return last
+}
@@ -2,8 +2,8 @@ package atom
 
 import (
 	"fmt"
-	"regexp"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
@@ -12,16 +12,26 @@ import (
 
 var updateFixturesPtr = test.UpdateFixtureFlag()
 
+func testClock() time.Time {
+	return test.Time(10)
+}
+
 func defaultHTMLRenderer() *HTMLRenderer {
-	return NewHTMLRenderer(DefaultSettings())
+	settings := DefaultSettings()
+	settings.Clock = testClock
+	return NewHTMLRenderer(settings)
 }
 
-func TestHtmlRenderer_Render(t *testing.T) {
-	htmlEntries := []*HTMLEntry{
-		{"first", "num #1", test.Time(1), "<p>The story starts here</p>", "The sum", test.Time(2)},
-		{"second", "num #2", test.Time(3), "<p>The story is in the middle here</p>", "The sum of it all", test.Time(4)},
-		{"third", "num #3", test.Time(5), "<p>The story ends here</p>", "The sum of the conclusion", test.Time(6)},
+func defaultHTMLEntries() []*HTMLEntry {
+	return []*HTMLEntry{
+		{ID: "first", Title: "num #1", Updated: test.Time(1), HTMLContent: "<p>The story starts here</p>", Summary: "The sum", Published: test.Time(2)},
+		{ID: "second", Title: "num #2", Updated: test.Time(3), HTMLContent: "<p>The story is in the middle here</p>", Summary: "The sum of it all", Published: test.Time(4)},
+		{ID: "third", Title: "num #3", Updated: test.Time(5), HTMLContent: "<p>The story ends here</p>", Summary: "The sum of the conclusion", Published: test.Time(6)},
 	}
+}
+
+func TestHtmlRenderer_Render(t *testing.T) {
+	htmlEntries := defaultHTMLEntries()
 
 	testCases := []struct {
 		htmlEntries []*HTMLEntry
@@ -43,11 +53,6 @@ func TestHtmlRenderer_Render(t *testing.T) {
 		}
 
 		got := string(bytes)
-		if len(tc.htmlEntries) == 0 {
-			regex := regexp.MustCompile(`<updated>[^<].*</updated>`)
-			got = regex.ReplaceAllString(got, "<updated>REPLACED time.Now()</updated>")
-		}
-
 		fixtureFilename := fmt.Sprintf("feed%v.xml", testCaseIndex)
 		if *updateFixturesPtr {
 			test.WriteFixture(t, fixtureFilename, []byte(got))
@@ -60,3 +65,109 @@ func TestHtmlRenderer_Render(t *testing.T) {
 		}
 	}
 }
+
+func TestHTMLEntriesToFeed_Authors(t *testing.T) {
+	guest := &Author{Name: "Guest Name", URI: "https://guest.com", Email: "guest@guest.com"}
+	helper := &Contributor{Name: "Helper Name"}
+
+	testCases := []struct {
+		authorName        string
+		entryAuthors      []*Author
+		expFeedAuthors    int
+		expEntryAuthors   []string
+		expMissingAuthors int
+	}{
+		{"Your Name", nil, 1, []string{"Your Name", "Your Name"}, 0},
+		{"Your Name", []*Author{guest}, 1, []string{"Guest Name", "Your Name"}, 0},
+		{"", []*Author{guest}, 0, []string{"Guest Name", ""}, 1},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":      testCaseIndex,
+			"authorName": tc.authorName,
+		})
+
+		renderer := defaultRenderer()
+		renderer.Settings.AuthorName = tc.authorName
+		htmlEntries := defaultHTMLEntries()[0:2]
+		htmlEntries[0].Authors = tc.entryAuthors
+		htmlEntries[0].Contributors = []*Contributor{helper}
+
+		feed := HTMLEntriesToFeed(renderer, "posts", "posts.atom", "logo.png", htmlEntries)
+		if len(feed.Authors) != tc.expFeedAuthors {
+			t.Error(context.GotExpString("len(feed.Authors)", len(feed.Authors), tc.expFeedAuthors))
+		}
+		for i, entry := range feed.Entries {
+			got := ""
+			if len(entry.Authors) > 0 {
+				got = entry.Authors[0].Name
+			}
+			if got != tc.expEntryAuthors[i] {
+				t.Error(context.GotExpString(fmt.Sprintf("feed.Entries[%v].Authors[0].Name", i), got, tc.expEntryAuthors[i]))
+			}
+		}
+		if feed.Entries[0].Contributors[0] != helper {
+			t.Error(context.GotExpString("feed.Entries[0].Contributors[0]", feed.Entries[0].Contributors[0], helper))
+		}
+
+		missingAuthors := 0
+		for _, violation := range feed.Validate() {
+			if violation.Kind == MissingAuthorViolation {
+				missingAuthors++
+			}
+		}
+		if missingAuthors != tc.expMissingAuthors {
+			t.Error(context.GotExpString("MissingAuthorViolation count", missingAuthors, tc.expMissingAuthors))
+		}
+	}
+}
+
+func TestLastUpdated(t *testing.T) {
+	settings := DefaultSettings()
+	settings.Clock = testClock
+
+	testCases := []struct {
+		updated []int
+		exp     time.Time
+	}{
+		{[]int{}, testClock()},
+		{[]int{3}, test.Time(3)},
+		{[]int{1, 5, 3}, test.Time(5)},
+		{[]int{6, 2, 4}, test.Time(6)},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":   testCaseIndex,
+			"updated": tc.updated,
+		})
+
+		htmlEntries := make([]*HTMLEntry, len(tc.updated))
+		for i, day := range tc.updated {
+			htmlEntries[i] = &HTMLEntry{Updated: test.Time(day)}
+		}
+
+		got := LastUpdated(settings, htmlEntries)
+		if !got.Equal(tc.exp) {
+			t.Error(context.GotExpString("Result", got, tc.exp))
+		}
+	}
+}
+
+func TestHtmlRenderer_Render_Deterministic(t *testing.T) {
+	renderer := defaultHTMLRenderer()
+	renderer.Settings.Clock = nil
+
+	first, err := renderer.Render("posts", "posts.atom", "logo.png", defaultHTMLEntries())
+	if err != nil {
+		t.Error(err)
+	}
+	second, err := renderer.Render("posts", "posts.atom", "logo.png", defaultHTMLEntries())
+	if err != nil {
+		t.Error(err)
+	}
+	if string(first) != string(second) {
+		t.Errorf("renders differ: %v != %v", string(first), string(second))
+	}
+}
@@ -0,0 +1,112 @@
+package atom
+
+import (
+	"sort"
+	"time"
+)
+
+// HTMLArchive is the data of a HTML archive page listing the entries of a feed, grouped by year and month
+// for a html/template. The entries are the same as in the feed, see HTMLRenderer.Archive.
+type HTMLArchive struct {
+	Title string
+	// FeedURL is the full URL of the feed the archive lists
+	FeedURL string
+	// Count is the number of entries in the archive
+	Count int
+	// Years are the years with entries, newest first
+	Years []*ArchiveYear
+}
+
+// ArchiveYear is a year of a HTMLArchive
+type ArchiveYear struct {
+	Year  int
+	Count int
+	// Months are the months of the year with entries, newest first
+	Months []*ArchiveMonth
+}
+
+// ArchiveMonth is a month of a HTMLArchive
+type ArchiveMonth struct {
+	Year  int
+	Month time.Month
+	Count int
+	// Entries are the entries of the month, newest first
+	Entries []*ArchiveEntry
+}
+
+// ArchiveEntry is a HTMLEntry of a HTMLArchive, with the URL of its page
+type ArchiveEntry struct {
+	*HTMLEntry
+	// URL is the full URL of the page of the entry, as in its rel="alternate" link in the feed
+	URL string
+	// Date is the date the entry is archived under, HTMLEntry.Published or HTMLEntry.Updated when it's zero
+	Date time.Time
+
+	// Prev is the entry before this one in time, nil for the oldest entry
+	Prev *ArchiveEntry
+	// Next is the entry after this one in time, nil for the newest entry
+	Next *ArchiveEntry
+}
+
+// Archive returns the HTMLArchive of the htmlEntries of the feed at selfURL. The entries are processed like Render,
+// so the archive matches the feed, but all entries are listed, regardless of the EntryLimit.
+// Entries with the same date keep the order of htmlEntries.
+func (renderer *HTMLRenderer) Archive(feedName, selfURL string, htmlEntries []*HTMLEntry) (*HTMLArchive, error) {
+	htmlEntries, err := renderer.processEntries(htmlEntries)
+	if err != nil {
+		return nil, err
+	}
+	atomRenderer := renderer.atomRenderer()
+
+	entries := make([]*ArchiveEntry, len(htmlEntries))
+	for i, htmlEntry := range htmlEntries {
+		entries[i] = &ArchiveEntry{HTMLEntry: htmlEntry, URL: atomRenderer.AlternateLink(htmlEntry.ID).Href, Date: archiveDate(htmlEntry)}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date.After(entries[j].Date)
+	})
+	for i, entry := range entries {
+		if i > 0 {
+			entry.Next = entries[i-1]
+		}
+		if i < len(entries)-1 {
+			entry.Prev = entries[i+1]
+		}
+	}
+
+	return &HTMLArchive{
+		Title:   atomRenderer.Title(feedName),
+		FeedURL: atomRenderer.Settings.FullURLFor(selfURL),
+		Count:   len(entries),
+		Years:   archiveYears(entries),
+	}, nil
+}
+
+func archiveDate(htmlEntry *HTMLEntry) time.Time {
+	if htmlEntry.Published.IsZero() {
+		return htmlEntry.Updated
+	}
+	return htmlEntry.Published
+}
+
+// archiveYears groups the entries sorted newest first by year and month
+func archiveYears(entries []*ArchiveEntry) []*ArchiveYear {
+	var years []*ArchiveYear
+	var year *ArchiveYear
+	var month *ArchiveMonth
+	for _, entry := range entries {
+		if year == nil || year.Year != entry.Date.Year() {
+			year = &ArchiveYear{Year: entry.Date.Year()}
+			years = append(years, year)
+			month = nil
+		}
+		if month == nil || month.Month != entry.Date.Month() {
+			month = &ArchiveMonth{Year: year.Year, Month: entry.Date.Month()}
+			year.Months = append(year.Months, month)
+		}
+		year.Count++
+		month.Count++
+		month.Entries = append(month.Entries, entry)
+	}
+	return years
+}
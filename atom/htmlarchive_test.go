@@ -0,0 +1,147 @@
+package atom
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+const archiveTemplate = `<h1>{{ .Title }}</h1>
+<a href="{{ .FeedURL }}">{{ .Count }} entries</a>
+{{- range .Years }}
+<h2>{{ .Year }} ({{ .Count }})</h2>
+{{- range .Months }}
+<h3>{{ .Month }} {{ .Year }} ({{ .Count }})</h3>
+<ul>
+{{- range .Entries }}
+<li><a href="{{ .URL }}">{{ .Title }}</a> {{ .Date.Format "Jan 2" }}
+{{- with .Prev }} prev: <a href="{{ .URL }}">{{ .Title }}</a>{{ end }}
+{{- with .Next }} next: <a href="{{ .URL }}">{{ .Title }}</a>{{ end }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- end }}
+`
+
+func archiveHTMLEntries() []*HTMLEntry {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	return []*HTMLEntry{
+		{ID: "new-year", Title: "New Year", Updated: date(2018, time.January, 1), Published: date(2018, time.January, 1)},
+		{ID: "draft", Title: "Draft <b>", Updated: date(2018, time.March, 3)},
+		{ID: "spring", Title: "Spring", Updated: date(2018, time.March, 20), Published: date(2018, time.March, 20)},
+		{ID: "winter", Title: "Winter", Updated: date(2017, time.December, 21), Published: date(2017, time.December, 21)},
+		{ID: "same-day", Title: "Same Day", Updated: date(2018, time.March, 20), Published: date(2018, time.March, 20)},
+	}
+}
+
+func archiveSummary(archive *HTMLArchive) []string {
+	var summary []string
+	for _, year := range archive.Years {
+		for _, month := range year.Months {
+			ids := make([]string, len(month.Entries))
+			for i, entry := range month.Entries {
+				ids[i] = entry.ID
+			}
+			summary = append(summary, fmt.Sprintf("%v(%v) %v(%v): %v", year.Year, year.Count, month.Month, month.Count, ids))
+		}
+	}
+	return summary
+}
+
+func TestHTMLRenderer_Archive(t *testing.T) {
+	testCases := []struct {
+		htmlEntries []*HTMLEntry
+		exp         []string
+	}{
+		{nil, nil},
+		{archiveHTMLEntries()[:1], []string{"2018(1) January(1): [new-year]"}},
+		{archiveHTMLEntries(), []string{
+			"2018(4) March(3): [spring same-day draft]",
+			"2018(4) January(1): [new-year]",
+			"2017(1) December(1): [winter]",
+		}},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+		})
+
+		archive, err := defaultHTMLRenderer().Archive("posts", "posts.atom", tc.htmlEntries)
+		if err != nil {
+			t.Error(context.String(err))
+			continue
+		}
+		test.AssertLabel(t, context.String("Count"), archive.Count, len(tc.htmlEntries))
+		got := archiveSummary(archive)
+		if !cmp.Equal(got, tc.exp) {
+			t.Error(context.DiffString("Years", got, tc.exp, cmp.Diff(got, tc.exp)))
+		}
+	}
+}
+
+func TestHTMLRenderer_Archive_PrevNext(t *testing.T) {
+	archive, err := defaultHTMLRenderer().Archive("posts", "posts.atom", archiveHTMLEntries())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, year := range archive.Years {
+		for _, month := range year.Months {
+			for _, entry := range month.Entries {
+				prev, next := "", ""
+				if entry.Prev != nil {
+					prev = entry.Prev.ID
+				}
+				if entry.Next != nil {
+					next = entry.Next.ID
+				}
+				got = append(got, fmt.Sprintf("%v<%v>%v", prev, entry.ID, next))
+			}
+		}
+	}
+	exp := []string{
+		"same-day<spring>",
+		"draft<same-day>spring",
+		"new-year<draft>same-day",
+		"winter<new-year>draft",
+		"<winter>new-year",
+	}
+	if !cmp.Equal(got, exp) {
+		t.Error(test.NewContext().DiffString("Prev/Next", got, exp, cmp.Diff(got, exp)))
+	}
+}
+
+func TestHTMLRenderer_Archive_Template(t *testing.T) {
+	archive, err := defaultHTMLRenderer().Archive("posts", "posts.atom", archiveHTMLEntries())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := &bytes.Buffer{}
+	err = template.Must(template.New("archive").Parse(archiveTemplate)).Execute(buffer, archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := buffer.String()
+	fixtureFilename := "archive.html"
+	if *updateFixturesPtr {
+		test.WriteFixture(t, fixtureFilename, []byte(got))
+		return
+	}
+
+	exp := string(test.ReadFixture(t, fixtureFilename))
+	if got != exp {
+		t.Error(test.NewContext().DiffString("Template", got, exp, cmp.Diff(got, exp)))
+	}
+}
@@ -0,0 +1,133 @@
+package atom
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// JSONFeedVersion is the URL of the JSON Feed version outputted
+const JSONFeedVersion = "https://jsonfeed.org/version/1.1"
+
+// JSONFeedMediaType is the media type of JSON Feeds
+const JSONFeedMediaType = "application/feed+json"
+
+// JSONFeed represents the entire JSON Feed
+type JSONFeed struct {
+	Version     string `json:"version"`
+	Title       string `json:"title"`
+	HomePageURL string `json:"home_page_url,omitempty"`
+	FeedURL     string `json:"feed_url,omitempty"`
+	Icon        string `json:"icon,omitempty"`
+	Language    string `json:"language,omitempty"`
+
+	Authors []*JSONFeedAuthor `json:"authors,omitempty"`
+	Items   []*JSONFeedItem   `json:"items"`
+}
+
+// Marhshall returns the JSON data of the feed
+func (feed *JSONFeed) Marhshall() ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(feed)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// JSONFeedAuthor represents an author in the JSON Feed
+type JSONFeedAuthor struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// JSONFeedItem represents an item in the JSON Feed
+type JSONFeedItem struct {
+	ID    string `json:"id"`
+	URL   string `json:"url,omitempty"`
+	Title string `json:"title,omitempty"`
+
+	ContentHTML string `json:"content_html,omitempty"`
+	Summary     string `json:"summary,omitempty"`
+
+	Authors []*JSONFeedAuthor `json:"authors,omitempty"`
+
+	DatePublished *time.Time `json:"date_published,omitempty"`
+	DateModified  *time.Time `json:"date_modified,omitempty"`
+}
+
+// ToJSONFeedItem converts the HTMLEntry to a JSON Feed item
+func (htmlEntry *HTMLEntry) ToJSONFeedItem(a *Renderer) *JSONFeedItem {
+	return &JSONFeedItem{
+		ID:    a.Settings.EntryID(htmlEntry.ID, htmlEntry.Published),
+		URL:   a.Settings.FullURLFor(htmlEntry.ID),
+		Title: htmlEntry.Title,
+
+		ContentHTML: htmlEntry.HTMLContent,
+		Summary:     htmlEntry.Summary,
+
+		Authors: toJSONFeedAuthors(htmlEntry.Authors),
+
+		DatePublished: jsonFeedTime(htmlEntry.Published),
+		DateModified:  jsonFeedTime(htmlEntry.Updated),
+	}
+}
+
+func toJSONFeedAuthors(authors []*Author) []*JSONFeedAuthor {
+	if len(authors) == 0 {
+		return nil
+	}
+	jsonAuthors := make([]*JSONFeedAuthor, len(authors))
+	for i, author := range authors {
+		jsonAuthors[i] = &JSONFeedAuthor{Name: author.Name, URL: author.URI}
+	}
+	return jsonAuthors
+}
+
+func jsonFeedTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// JSONFeedRenderer is a "main struct", which generates a JSON Feed from the same inputs as HTMLRenderer
+type JSONFeedRenderer struct {
+	Settings *Settings
+}
+
+// NewJSONFeedRenderer returns a new instance of JSONFeedRenderer
+func NewJSONFeedRenderer(settings *Settings) *JSONFeedRenderer {
+	return &JSONFeedRenderer{settings}
+}
+
+// Render returns the JSON data of a generated feed
+func (renderer *JSONFeedRenderer) Render(feedName, selfURL, logoURL string, htmlEntries []*HTMLEntry) ([]byte, error) {
+	atomRenderer := NewRenderer(renderer.Settings)
+	feed := HTMLEntriesToJSONFeed(atomRenderer, feedName, selfURL, logoURL, htmlEntries)
+	return feed.Marhshall()
+}
+
+// HTMLEntriesToJSONFeed converts []HTMLEntry to a JSONFeed, with lots of defaults set
+func HTMLEntriesToJSONFeed(atomRenderer *Renderer, feedName, selfURL, logoURL string, htmlEntries []*HTMLEntry) *JSONFeed {
+	items := make([]*JSONFeedItem, len(htmlEntries))
+	for i, htmlEntry := range htmlEntries {
+		items[i] = htmlEntry.ToJSONFeedItem(atomRenderer)
+	}
+
+	settings := atomRenderer.Settings
+	return &JSONFeed{
+		Version:     JSONFeedVersion,
+		Title:       atomRenderer.Title(feedName),
+		HomePageURL: settings.URL(),
+		FeedURL:     settings.FullURLFor(selfURL),
+		Icon:        settings.FullURLFor(logoURL),
+		Language:    atomRenderer.Settings.FeedOptions.LanguageDefaulted(),
+
+		Authors: toJSONFeedAuthors(atomRenderer.Authors()),
+		Items:   items,
+	}
+}
@@ -0,0 +1,56 @@
+package atom
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func defaultJSONFeedRenderer() *JSONFeedRenderer {
+	return NewJSONFeedRenderer(DefaultSettings())
+}
+
+func TestJSONFeedRenderer_Render(t *testing.T) {
+	htmlEntries := defaultHTMLEntries()
+	htmlEntries[2].Summary = ""
+	htmlEntries[2].Published = time.Time{}
+
+	testCases := []struct {
+		htmlEntries []*HTMLEntry
+	}{
+		{htmlEntries},
+		{htmlEntries[0:1]},
+		{[]*HTMLEntry{}},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+		})
+
+		bytes, err := defaultJSONFeedRenderer().Render("the test feed for posts", "posts.json", "logo.png", tc.htmlEntries)
+		if err != nil {
+			t.Error(context.String(err))
+		}
+		if !json.Valid(bytes) {
+			t.Error(context.String("invalid JSON"))
+		}
+
+		got := string(bytes)
+		fixtureFilename := fmt.Sprintf("jsonfeed%v.json", testCaseIndex)
+		if *updateFixturesPtr {
+			test.WriteFixture(t, fixtureFilename, []byte(got))
+			continue
+		}
+
+		exp := string(test.ReadFixture(t, fixtureFilename))
+		if got != exp {
+			t.Error(context.DiffString("JSONFeedRenderer.Render", got, exp, cmp.Diff(got, exp)))
+		}
+	}
+}
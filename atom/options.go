@@ -0,0 +1,76 @@
+package atom
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// DefaultLanguage is the default xml:lang of the feeds
+const DefaultLanguage = "en-US"
+
+// DefaultTitleFormat is the default FeedOptions.TitleFormat
+const DefaultTitleFormat = "{name} - {host}"
+
+// FeedOptions are the optional metadata of a Feed
+type FeedOptions struct {
+	Subtitle   string      `json:"subtitle,omitempty"`
+	Rights     string      `json:"rights,omitempty"`
+	Generator  *Generator  `json:"generator,omitempty"`
+	Categories []*Category `json:"categories,omitempty"`
+	// LogoURL is the URL of the large logo image of the feed, as opposed to the small icon
+	LogoURL string `json:"logo_url,omitempty"`
+
+	// Language is the xml:lang of the feed, defaults to DefaultLanguage
+	Language string `json:"language,omitempty"`
+
+	// TitleFormat formats the feed title, where {name} is replaced by the feed name and {host} by Settings.Host.
+	// Defaults to DefaultTitleFormat.
+	TitleFormat string `json:"title_format,omitempty"`
+	// RawName keeps the feed name as is in the title, instead of capitalizing each word
+	RawName bool `json:"raw_name,omitempty"`
+}
+
+// Generator represents the generator of the Atom feed
+type Generator struct {
+	XMLName xml.Name `xml:"generator" json:"-"`
+
+	URI     string `xml:"uri,attr,omitempty" json:"uri,omitempty"`
+	Version string `xml:"version,attr,omitempty" json:"version,omitempty"`
+	Name    string `xml:",chardata" json:"name"`
+}
+
+// LanguageDefaulted returns a defaulted Language
+func (options *FeedOptions) LanguageDefaulted() string {
+	if options == nil || options.Language == "" {
+		return DefaultLanguage
+	}
+	return options.Language
+}
+
+// Title returns the title of the feed with the given feedName on the host, see TitleFormat
+func (options *FeedOptions) Title(feedName, host string) string {
+	format := DefaultTitleFormat
+	if options != nil && options.TitleFormat != "" {
+		format = options.TitleFormat
+	}
+	if options == nil || !options.RawName {
+		feedName = strings.Title(feedName)
+	}
+	return strings.NewReplacer("{name}", feedName, "{host}", host).Replace(format)
+}
+
+// setOptions sets the elements of the options on the feed, with full URLs from the settings
+func (feed *Feed) setOptions(settings *Settings, options *FeedOptions) {
+	feed.XMLLang = options.LanguageDefaulted()
+	if options == nil {
+		return
+	}
+
+	feed.Subtitle = options.Subtitle
+	feed.Rights = options.Rights
+	feed.Generator = options.Generator
+	feed.Categories = append([]*Category(nil), options.Categories...)
+	if options.LogoURL != "" {
+		feed.Logo = settings.FullURLFor(options.LogoURL)
+	}
+}
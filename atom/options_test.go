@@ -0,0 +1,126 @@
+package atom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func TestFeedOptions_Title(t *testing.T) {
+	testCases := []struct {
+		options *FeedOptions
+		exp     string
+	}{
+		{nil, "The Posts - yourwebsite.com"},
+		{&FeedOptions{}, "The Posts - yourwebsite.com"},
+		{&FeedOptions{RawName: true}, "the posts - yourwebsite.com"},
+		{&FeedOptions{TitleFormat: "{name}"}, "The Posts"},
+		{&FeedOptions{TitleFormat: "{host}: {name} ({name})", RawName: true}, "yourwebsite.com: the posts (the posts)"},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":   testCaseIndex,
+			"options": tc.options,
+		})
+
+		got := tc.options.Title("the posts", "yourwebsite.com")
+		if got != tc.exp {
+			t.Error(context.GotExpString("Result", got, tc.exp))
+		}
+	}
+}
+
+func TestFeedOptions_LanguageDefaulted(t *testing.T) {
+	testCases := []struct {
+		options *FeedOptions
+		exp     string
+	}{
+		{nil, DefaultLanguage},
+		{&FeedOptions{}, DefaultLanguage},
+		{&FeedOptions{Language: "fr-CA"}, "fr-CA"},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":   testCaseIndex,
+			"options": tc.options,
+		})
+
+		got := tc.options.LanguageDefaulted()
+		if got != tc.exp {
+			t.Error(context.GotExpString("Result", got, tc.exp))
+		}
+	}
+}
+
+func frenchFeedOptions() *FeedOptions {
+	return &FeedOptions{
+		Subtitle:    "Les articles en français",
+		Rights:      "© 2018 Your Name",
+		Generator:   &Generator{URI: "https://github.com/s12chung/gostatic", Version: "1.0", Name: "gostatic"},
+		Categories:  []*Category{{Term: "français", Label: "Français"}},
+		LogoURL:     "images/logo-large.png",
+		Language:    "fr-CA",
+		TitleFormat: "{name} | {host}",
+		RawName:     true,
+	}
+}
+
+func TestHtmlRenderer_Render_FeedOptions(t *testing.T) {
+	htmlEntries := defaultHTMLEntries()
+	htmlEntries[1].Language = "en-US"
+
+	renderer := defaultHTMLRenderer()
+	renderer.FeedOptions = frenchFeedOptions()
+	bytes, err := renderer.Render("les articles", "fr/posts.atom", "logo.png", htmlEntries[0:2])
+	if err != nil {
+		t.Error(err)
+	}
+
+	got := string(bytes)
+	fixtureFilename := "options.xml"
+	if *updateFixturesPtr {
+		test.WriteFixture(t, fixtureFilename, []byte(got))
+		return
+	}
+
+	exp := string(test.ReadFixture(t, fixtureFilename))
+	if got != exp {
+		t.Error(test.NewContext().DiffString("HTMLRenderer.Render", got, exp, cmp.Diff(got, exp)))
+	}
+
+	tagged, err := renderer.RenderTags("les articles", "fr/posts.atom", "logo.png", []*HTMLEntry{{ID: "a", Tags: []string{"go"}}})
+	if err != nil {
+		t.Error(err)
+	}
+	feed, err := Parse(strings.NewReader(string(tagged["go"])))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var terms []string
+	for _, category := range feed.Categories {
+		terms = append(terms, category.Term)
+	}
+	expTerms := []string{"français", "go"}
+	if !cmp.Equal(terms, expTerms) {
+		t.Error(test.NewContext().DiffString("tag Categories", terms, expTerms, cmp.Diff(terms, expTerms)))
+	}
+	test.AssertLabel(t, "len(FeedOptions.Categories)", len(renderer.FeedOptions.Categories), 1)
+}
+
+func TestHTMLEntriesToRSS_FeedOptions(t *testing.T) {
+	settings := DefaultSettings()
+	settings.Clock = testClock
+	settings.FeedOptions = frenchFeedOptions()
+
+	rss := HTMLEntriesToRSS(NewRenderer(settings), "les articles", "fr/posts.rss", "logo.png", nil)
+	test.AssertLabel(t, "Language", rss.Channel.Language, "fr-CA")
+	test.AssertLabel(t, "Title", rss.Channel.Title, "les articles | yourwebsite.com")
+
+	jsonFeed := HTMLEntriesToJSONFeed(NewRenderer(settings), "les articles", "fr/posts.json", "logo.png", nil)
+	test.AssertLabel(t, "Language", jsonFeed.Language, "fr-CA")
+}
@@ -0,0 +1,102 @@
+package atom
+
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
+// FeedPage is a rendered document of a paged feed, with the URL it should be routed to
+type FeedPage struct {
+	URL   string
+	Bytes []byte
+}
+
+// ArchiveURL returns the URL of the given archive page of the feed at selfURL: "/posts.atom" => "/posts/archive/1.atom"
+func ArchiveURL(selfURL string, page int) string {
+	ext := path.Ext(selfURL)
+	return path.Join(strings.TrimSuffix(selfURL, ext), "archive", strconv.Itoa(page)) + ext
+}
+
+// RenderPages renders the feed as a current feed and archive feeds (RFC 5005) with at most EntryLimit entries each.
+// htmlEntries are expected to be sorted newest first, so the archives are filled from the oldest entry and
+// don't change when new entries are added.
+//
+// The first FeedPage is the current feed at selfURL, followed by the archives from oldest (ArchiveURL(selfURL, 1)) to newest.
+func (renderer *HTMLRenderer) RenderPages(feedName, selfURL, logoURL string, htmlEntries []*HTMLEntry) ([]*FeedPage, error) {
+	htmlEntries, err := renderer.processEntries(htmlEntries)
+	if err != nil {
+		return nil, err
+	}
+	feeds := HTMLEntriesToFeedPages(renderer.atomRenderer(), renderer.EntryLimit, feedName, selfURL, logoURL, htmlEntries)
+
+	pages := make([]*FeedPage, len(feeds))
+	for i, feed := range feeds {
+		bytes, err := renderer.marshall(feed)
+		if err != nil {
+			return nil, err
+		}
+		url := selfURL
+		if i > 0 {
+			url = ArchiveURL(selfURL, i)
+		}
+		pages[i] = &FeedPage{url, bytes}
+	}
+	return pages, nil
+}
+
+// HTMLEntriesToFeedPages converts []HTMLEntry to the current Feed, followed by the archive Feeds from oldest to newest,
+// with the RFC 5005 links between them. A entryLimit <= 0 defaults to EntryLimit.
+func HTMLEntriesToFeedPages(atomRenderer *Renderer, entryLimit int, feedName, selfURL, logoURL string, htmlEntries []*HTMLEntry) []*Feed {
+	if entryLimit <= 0 {
+		entryLimit = EntryLimit
+	}
+
+	archiveCount := 0
+	if len(htmlEntries) > 0 {
+		archiveCount = (len(htmlEntries) - 1) / entryLimit
+	}
+	currentCount := len(htmlEntries) - archiveCount*entryLimit
+
+	feeds := make([]*Feed, archiveCount+1)
+	feeds[0] = HTMLEntriesToFeed(atomRenderer, feedName, selfURL, logoURL, htmlEntries[:currentCount])
+	for page := 1; page <= archiveCount; page++ {
+		end := len(htmlEntries) - (page-1)*entryLimit
+		feed := HTMLEntriesToFeed(atomRenderer, feedName, ArchiveURL(selfURL, page), logoURL, htmlEntries[end-entryLimit:end])
+		feed.XMLNSFH = HistoryNamespace
+		feed.Archive = &Archive{}
+		feeds[page] = feed
+	}
+
+	if archiveCount == 0 {
+		return feeds
+	}
+	feeds[0].Links = append(feeds[0].Links, archiveLinks(atomRenderer, selfURL, archiveCount, 0)...)
+	for page := 1; page <= archiveCount; page++ {
+		feeds[page].Links = append(feeds[page].Links, archiveLinks(atomRenderer, selfURL, archiveCount, page)...)
+	}
+	return feeds
+}
+
+func archiveLinks(atomRenderer *Renderer, selfURL string, archiveCount, page int) []*Link {
+	link := func(rel, url string) *Link {
+		return &Link{Rel: rel, Type: AtomMediaType, Href: atomRenderer.Settings.FullURLFor(url)}
+	}
+
+	links := []*Link{
+		link("first", selfURL),
+		link("last", ArchiveURL(selfURL, 1)),
+	}
+	if page == 0 {
+		return append(links, link("prev-archive", ArchiveURL(selfURL, archiveCount)))
+	}
+
+	links = append(links, link("current", selfURL))
+	if page > 1 {
+		links = append(links, link("prev-archive", ArchiveURL(selfURL, page-1)))
+	}
+	if page < archiveCount {
+		links = append(links, link("next-archive", ArchiveURL(selfURL, page+1)))
+	}
+	return links
+}
@@ -0,0 +1,132 @@
+package atom
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func TestArchiveURL(t *testing.T) {
+	testCases := []struct {
+		selfURL string
+		page    int
+		exp     string
+	}{
+		{"/posts.atom", 1, "/posts/archive/1.atom"},
+		{"posts.atom", 12, "posts/archive/12.atom"},
+		{"posts", 2, "posts/archive/2"},
+		{"/feeds/posts.xml", 3, "/feeds/posts/archive/3.xml"},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":   testCaseIndex,
+			"selfURL": tc.selfURL,
+			"page":    tc.page,
+		})
+
+		got := ArchiveURL(tc.selfURL, tc.page)
+		if got != tc.exp {
+			t.Error(context.GotExpString("Result", got, tc.exp))
+		}
+	}
+}
+
+func pagingHTMLEntries(count int) []*HTMLEntry {
+	htmlEntries := make([]*HTMLEntry, count)
+	for i := range htmlEntries {
+		day := count - i
+		htmlEntries[i] = &HTMLEntry{
+			ID:          fmt.Sprintf("post-%v", day),
+			Title:       fmt.Sprintf("num #%v", day),
+			Updated:     test.Time(day),
+			HTMLContent: fmt.Sprintf("<p>Story %v</p>", day),
+			Summary:     fmt.Sprintf("The sum %v", day),
+			Published:   test.Time(day),
+		}
+	}
+	return htmlEntries
+}
+
+func TestHTMLRenderer_RenderPages(t *testing.T) {
+	testCases := []struct {
+		entryCount int
+		entryLimit int
+		expURLs    []string
+	}{
+		{0, 2, []string{"posts.atom"}},
+		{2, 2, []string{"posts.atom"}},
+		{3, 2, []string{"posts.atom", "posts/archive/1.atom"}},
+		{4, 2, []string{"posts.atom", "posts/archive/1.atom"}},
+		{5, 2, []string{"posts.atom", "posts/archive/1.atom", "posts/archive/2.atom"}},
+		{5, 0, []string{"posts.atom"}},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":      testCaseIndex,
+			"entryCount": tc.entryCount,
+			"entryLimit": tc.entryLimit,
+		})
+
+		renderer := defaultHTMLRenderer()
+		renderer.EntryLimit = tc.entryLimit
+		pages, err := renderer.RenderPages("posts", "posts.atom", "logo.png", pagingHTMLEntries(tc.entryCount))
+		if err != nil {
+			t.Error(context.String(err))
+			continue
+		}
+
+		gotURLs := make([]string, len(pages))
+		for i, page := range pages {
+			gotURLs[i] = page.URL
+		}
+		if !cmp.Equal(gotURLs, tc.expURLs) {
+			t.Error(context.GotExpString("URLs", gotURLs, tc.expURLs))
+		}
+
+		for _, page := range pages {
+			feed, err := Parse(bytes.NewReader(page.Bytes))
+			if err != nil {
+				t.Error(context.String(err))
+				continue
+			}
+			if len(feed.Entries) > renderer.EntryLimit && renderer.EntryLimit > 0 {
+				t.Error(context.Stringf("%v has %v entries, more than %v", page.URL, len(feed.Entries), renderer.EntryLimit))
+			}
+		}
+	}
+}
+
+func TestHTMLRenderer_RenderPages_Fixtures(t *testing.T) {
+	renderer := defaultHTMLRenderer()
+	renderer.EntryLimit = 2
+
+	pages, err := renderer.RenderPages("posts", "posts.atom", "logo.png", pagingHTMLEntries(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for pageIndex, page := range pages {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"pageIndex": pageIndex,
+			"URL":       page.URL,
+		})
+
+		got := string(page.Bytes)
+		fixtureFilename := fmt.Sprintf("paging%v.xml", pageIndex)
+		if *updateFixturesPtr {
+			test.WriteFixture(t, fixtureFilename, page.Bytes)
+			continue
+		}
+
+		exp := string(test.ReadFixture(t, fixtureFilename))
+		if got != exp {
+			t.Error(context.DiffString("HTMLRenderer.RenderPages", got, exp, cmp.Diff(got, exp)))
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package atom
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+)
+
+// Namespace is the XML namespace of Atom
+const Namespace = "http://www.w3.org/2005/Atom"
+
+// HistoryNamespace is the XML namespace of Feed Paging and Archiving (RFC 5005)
+const HistoryNamespace = "http://purl.org/syndication/history/1.0"
+
+// namespacePrefixes maps the XML namespaces to the prefixes used in the struct tags.
+// Atom is the default namespace, so it has no prefix.
+var namespacePrefixes = map[string]string{
+	Namespace:        "",
+	HistoryNamespace: "fh",
+	ITunesNamespace:  "itunes",
+
+	TombstonesNamespace: "at",
+	ThreadNamespace:     "thr",
+}
+
+// rssNamespacePrefixes is namespacePrefixes for RSS documents, where Atom elements have the "atom" prefix
+var rssNamespacePrefixes = map[string]string{
+	Namespace:        "atom",
+	ContentNamespace: "content",
+	DCNamespace:      "dc",
+	ITunesNamespace:  "itunes",
+}
+
+// rssDateLayouts are the date layouts found in RSS documents, RFC 822 and its common variations
+var rssDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+}
+
+// Parse parses the Atom xml data into a Feed. It reads back everything Feed.Marhshall outputs.
+func Parse(reader io.Reader) (*Feed, error) {
+	feed := &Feed{}
+	prefixReader := newPrefixReader(reader, namespacePrefixes)
+	err := xml.NewTokenDecoder(prefixReader).Decode(feed)
+	if err != nil {
+		return nil, err
+	}
+	feed.Stylesheet = prefixReader.stylesheet
+	return feed, nil
+}
+
+// ParseRSS parses the RSS xml data into a RSS document. It reads back everything RSS.Marhshall outputs.
+func ParseRSS(reader io.Reader) (*RSS, error) {
+	rss := &RSS{}
+	err := xml.NewTokenDecoder(newPrefixReader(reader, rssNamespacePrefixes)).Decode(rss)
+	if err != nil {
+		return nil, err
+	}
+	return rss, nil
+}
+
+// ParseRSSDate parses the date of a RSS document, which is in RFC 822 format or one of its common variations
+func ParseRSSDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	var err error
+	for _, layout := range rssDateLayouts {
+		var t time.Time
+		t, err = time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// prefixReader is a xml.TokenReader that renames namespaced names to
+// the "prefix:local" names used in the struct tags, i.e. "xml:lang".
+//
+// encoding/xml does not keep prefixes when decoding, so without this,
+// prefixed tags never match.
+type prefixReader struct {
+	decoder  *xml.Decoder
+	prefixes map[string]string
+	scopes   []map[string]string
+
+	// stylesheet is the href of the <?xml-stylesheet?> processing instruction
+	stylesheet string
+}
+
+func newPrefixReader(reader io.Reader, prefixes map[string]string) *prefixReader {
+	return &prefixReader{decoder: xml.NewDecoder(reader), prefixes: prefixes}
+}
+
+// Token returns the next token, with the names renamed
+func (reader *prefixReader) Token() (xml.Token, error) {
+	token, err := reader.decoder.RawToken()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case xml.StartElement:
+		reader.scopes = append(reader.scopes, namespaceScope(t.Attr))
+
+		t.Name = reader.renameElement(t.Name)
+		attrs := make([]xml.Attr, len(t.Attr))
+		for i, attr := range t.Attr {
+			attrs[i] = xml.Attr{Name: reader.rename(attr.Name), Value: attr.Value}
+		}
+		t.Attr = attrs
+		return t, nil
+	case xml.EndElement:
+		t.Name = reader.renameElement(t.Name)
+		if len(reader.scopes) > 0 {
+			reader.scopes = reader.scopes[:len(reader.scopes)-1]
+		}
+		return t, nil
+	case xml.ProcInst:
+		if t.Target == "xml-stylesheet" {
+			reader.stylesheet = stylesheetHref(t.Inst)
+		}
+	}
+	return xml.CopyToken(token), nil
+}
+
+// namespaceScope returns the namespaces declared by the attrs of an element, by prefix.
+// The default namespace has the "" prefix.
+func namespaceScope(attrs []xml.Attr) map[string]string {
+	scope := map[string]string{}
+	for _, attr := range attrs {
+		switch {
+		case attr.Name.Space == "xmlns":
+			scope[attr.Name.Local] = attr.Value
+		case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+			scope[""] = attr.Value
+		}
+	}
+	return scope
+}
+
+// stylesheetHref returns the href pseudo-attribute of the instruction of a <?xml-stylesheet?>
+func stylesheetHref(inst []byte) string {
+	pseudoAttributes := struct {
+		Href string `xml:"href,attr"`
+	}{}
+	err := xml.Unmarshal([]byte("<xml-stylesheet "+string(inst)+"/>"), &pseudoAttributes)
+	if err != nil {
+		return ""
+	}
+	return pseudoAttributes.Href
+}
+
+// renameElement renames the name of an element, which is in the default namespace when it has no prefix.
+// XHTML elements keep their namespace instead, so the div of xhtml EntryContent is found whatever its prefix.
+func (reader *prefixReader) renameElement(name xml.Name) xml.Name {
+	namespace := reader.namespace(name.Space)
+	if namespace == XHTMLNamespace {
+		return xml.Name{Space: XHTMLNamespace, Local: name.Local}
+	}
+	if name.Space == "" {
+		if prefix, contains := reader.prefixes[namespace]; contains && prefix != "" {
+			return xml.Name{Local: prefix + ":" + name.Local}
+		}
+	}
+	return reader.rename(name)
+}
+
+func (reader *prefixReader) rename(name xml.Name) xml.Name {
+	switch name.Space {
+	case "":
+		return name
+	case "xml", "xmlns":
+		return xml.Name{Local: name.Space + ":" + name.Local}
+	}
+
+	prefix, contains := reader.prefixes[reader.namespace(name.Space)]
+	if !contains {
+		prefix = name.Space
+	}
+	if prefix == "" {
+		return xml.Name{Local: name.Local}
+	}
+	return xml.Name{Local: prefix + ":" + name.Local}
+}
+
+func (reader *prefixReader) namespace(prefix string) string {
+	for i := len(reader.scopes) - 1; i >= 0; i-- {
+		if namespace, contains := reader.scopes[i][prefix]; contains {
+			return namespace
+		}
+	}
+	return ""
+}
@@ -0,0 +1,192 @@
+package atom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		fixtureFilename string
+	}{
+		{"new_feed.xml"},
+		{"feed0.xml"},
+		{"feed1.xml"},
+		{"feed2.xml"},
+		{"parse.xml"},
+		{"paging2.xml"},
+		{"tombstone.xml"},
+		{"options.xml"},
+		{"comments0.xml"},
+		{"replies.xml"},
+		{"extension.xml"},
+		{"content.xml"},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":           testCaseIndex,
+			"fixtureFilename": tc.fixtureFilename,
+		})
+
+		exp := string(test.ReadFixture(t, tc.fixtureFilename))
+		feed, err := Parse(strings.NewReader(exp))
+		if err != nil {
+			t.Error(context.String(err))
+			continue
+		}
+
+		bytes, err := feed.Marhshall()
+		if err != nil {
+			t.Error(context.String(err))
+		}
+		got := string(bytes)
+		if got != exp {
+			t.Error(context.DiffString("Parse(...).Marhshall()", got, exp, cmp.Diff(got, exp)))
+		}
+	}
+}
+
+func TestParse_Fields(t *testing.T) {
+	feed, err := Parse(bytes.NewReader(test.ReadFixture(t, "parse.xml")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.AssertLabel(t, "XMLLang", feed.XMLLang, "en-US")
+	test.AssertLabel(t, "XMLNS", feed.XMLNS, Namespace)
+	test.AssertLabel(t, "Authors[0].URI", feed.Authors[0].URI, "https://yourwebsite.com/about")
+	test.AssertLabel(t, "len(Entries)", len(feed.Entries), 2)
+
+	entry := feed.Entries[0]
+	test.AssertLabel(t, "Entry.Title", entry.Title, "num #1 & more")
+	test.AssertLabel(t, "len(Entry.Authors)", len(entry.Authors), 2)
+	test.AssertLabel(t, "Entry.Authors[1].URI", entry.Authors[1].URI, "https://guest.com")
+	test.AssertLabel(t, "Entry.Contributors[0].Email", entry.Contributors[0].Email, "helper@guest.com")
+	test.AssertLabel(t, "Entry.Content.Content", entry.Content.Content, "<p>The story <em>starts</em> here &amp; ]]> there</p>")
+	test.AssertLabel(t, "len(Entry.Links)", len(entry.Links), 2)
+	test.AssertLabel(t, "Entry.Links[1].Rel", entry.Links[1].Rel, "related")
+	test.AssertLabel(t, "Entry.Published", entry.Published, test.Time(2))
+
+	entry = feed.Entries[1]
+	if entry.Authors != nil || entry.Content != nil {
+		t.Errorf("Entries[1] Authors or Content not nil: %v, %v", entry.Authors, entry.Content)
+	}
+}
+
+func TestParse_Prefixed(t *testing.T) {
+	prefixed := `<?xml version="1.0" encoding="UTF-8"?>
+<a:feed xml:lang="fr" xmlns:a="http://www.w3.org/2005/Atom">
+  <a:id>yourwebsite.com:2018:prefixed</a:id>
+  <a:title>Prefixed</a:title>
+  <a:link rel="self" href="https://yourwebsite.com/prefixed"/>
+  <a:entry>
+    <a:id>yourwebsite.com:first:2018-01-02</a:id>
+    <a:title>num #1</a:title>
+  </a:entry>
+</a:feed>`
+
+	feed, err := Parse(strings.NewReader(prefixed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.AssertLabel(t, "XMLLang", feed.XMLLang, "fr")
+	test.AssertLabel(t, "ID", feed.ID, "yourwebsite.com:2018:prefixed")
+	test.AssertLabel(t, "Links[0].Href", feed.Links[0].Href, "https://yourwebsite.com/prefixed")
+	test.AssertLabel(t, "len(Entries)", len(feed.Entries), 1)
+	test.AssertLabel(t, "Entries[0].Title", feed.Entries[0].Title, "num #1")
+}
+
+func TestParse_Error(t *testing.T) {
+	testCases := []struct {
+		input string
+	}{
+		{""},
+		{"<feed><id>unclosed</feed>"},
+		{"not xml"},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+			"input": tc.input,
+		})
+
+		_, err := Parse(strings.NewReader(tc.input))
+		if err == nil {
+			t.Error(context.String("expected error, got nil"))
+		}
+	}
+}
+
+func TestParseRSS(t *testing.T) {
+	testCases := []struct {
+		fixtureFilename string
+	}{
+		{"rss0.xml"},
+		{"rss1.xml"},
+		{"rss3.xml"},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":           testCaseIndex,
+			"fixtureFilename": tc.fixtureFilename,
+		})
+
+		exp := string(test.ReadFixture(t, tc.fixtureFilename))
+		rss, err := ParseRSS(strings.NewReader(exp))
+		if err != nil {
+			t.Error(context.String(err))
+			continue
+		}
+
+		bytes, err := rss.Marhshall()
+		if err != nil {
+			t.Error(context.String(err))
+		}
+		got := string(bytes)
+		if got != exp {
+			t.Error(context.DiffString("ParseRSS(...).Marhshall()", got, exp, cmp.Diff(got, exp)))
+		}
+	}
+}
+
+func TestParseRSSDate(t *testing.T) {
+	exp := time.Date(2018, 1, 3, 10, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		value string
+		err   bool
+	}{
+		{"Wed, 03 Jan 2018 10:04:05 +0000", false},
+		{"Wed, 3 Jan 2018 10:04:05 +0000", false},
+		{"Wed, 03 Jan 2018 05:04:05 -0500", false},
+		{"Wed, 03 Jan 2018 10:04:05 GMT", false},
+		{" 3 Jan 2018 10:04:05 +0000 ", false},
+		{"2018-01-03T10:04:05Z", false},
+		{"yesterday", true},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+			"value": tc.value,
+		})
+
+		got, err := ParseRSSDate(tc.value)
+		if (err != nil) != tc.err {
+			t.Error(context.GotExpString("err", err, tc.err))
+			continue
+		}
+		if !tc.err && !got.Equal(exp) {
+			t.Error(context.GotExpString("Result", got, exp))
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package atom
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// ITunesNamespace is the XML namespace of the iTunes podcast elements
+const ITunesNamespace = "http://www.itunes.com/dtds/podcast-1.0.dtd"
+
+// Podcast represents the iTunes podcast elements of the Feed, which podcast directories require
+type Podcast struct {
+	Image      *ITunesImage      `xml:"itunes:image" json:"image,omitempty"`
+	Categories []*ITunesCategory `xml:"itunes:category" json:"categories,omitempty"`
+	Explicit   bool              `xml:"itunes:explicit" json:"explicit,omitempty"`
+	Author     string            `xml:"itunes:author,omitempty" json:"author,omitempty"`
+}
+
+// MarshalXML marshals the elements of the podcast without a parent element, so they are children of the Feed
+func (podcast *Podcast) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if podcast.Image != nil {
+		err := e.Encode(podcast.Image)
+		if err != nil {
+			return err
+		}
+	}
+	for _, category := range podcast.Categories {
+		err := e.Encode(category)
+		if err != nil {
+			return err
+		}
+	}
+	err := e.EncodeElement(podcast.Explicit, xml.StartElement{Name: xml.Name{Local: "itunes:explicit"}})
+	if err != nil || podcast.Author == "" {
+		return err
+	}
+	return e.EncodeElement(podcast.Author, xml.StartElement{Name: xml.Name{Local: "itunes:author"}})
+}
+
+// ITunesImage represents the artwork of the podcast
+type ITunesImage struct {
+	XMLName xml.Name `xml:"itunes:image" json:"-"`
+
+	Href string `xml:"href,attr" json:"href"`
+}
+
+// ITunesCategory represents a category of the podcast from the Apple Podcasts category list
+type ITunesCategory struct {
+	XMLName xml.Name `xml:"itunes:category" json:"-"`
+
+	Text        string          `xml:"text,attr" json:"text"`
+	Subcategory *ITunesCategory `xml:"itunes:category" json:"subcategory,omitempty"`
+}
+
+// PodcastEpisode represents the iTunes podcast elements of an Entry
+type PodcastEpisode struct {
+	Duration string `xml:"itunes:duration,omitempty"`
+	Episode  int    `xml:"itunes:episode,omitempty"`
+	Season   int    `xml:"itunes:season,omitempty"`
+	Explicit bool   `xml:"itunes:explicit,omitempty"`
+}
+
+// Enclosure is a file, like an audio file, that belongs to a HTMLEntry
+type Enclosure struct {
+	URL    string
+	Type   string
+	Length int64
+}
+
+// SetPodcast sets the Podcast elements of the feed and declares their namespace
+func (feed *Feed) SetPodcast(podcast *Podcast) {
+	feed.Podcast = podcast
+	feed.XMLNSITunes = ITunesNamespace
+}
+
+// Podcast returns Settings.Podcast with full URLs, nil if Settings.Podcast isn't set
+func (a *Renderer) Podcast() *Podcast {
+	if a.Settings.Podcast == nil {
+		return nil
+	}
+	podcast := *a.Settings.Podcast
+	if podcast.Image != nil {
+		podcast.Image = &ITunesImage{Href: a.Settings.FullURLFor(podcast.Image.Href)}
+	}
+	return &podcast
+}
+
+// EnclosureLink returns the rel="enclosure" Link of the enclosure
+func (a *Renderer) EnclosureLink(enclosure *Enclosure) *Link {
+	return &Link{Rel: "enclosure", Type: enclosure.Type, Href: a.Settings.FullURLFor(enclosure.URL), Length: enclosure.Length}
+}
+
+func (v *validator) podcast(feed *Feed) {
+	podcast := feed.Podcast
+	if podcast.Image == nil || podcast.Image.Href == "" {
+		v.add(MissingViolation, FeedIndex, "itunes:image", "is required for podcasts")
+	} else {
+		v.iri(FeedIndex, "itunes:image", podcast.Image.Href)
+	}
+	if len(podcast.Categories) == 0 {
+		v.add(MissingViolation, FeedIndex, "itunes:category", "is required for podcasts")
+	}
+
+	for i, entry := range feed.Entries {
+		v.episodeEnclosure(i, entry)
+	}
+}
+
+func (v *validator) episodeEnclosure(entryIndex int, entry *Entry) {
+	for i, link := range entry.Links {
+		if link.Rel != "enclosure" {
+			continue
+		}
+		if link.Type == "" {
+			v.add(MissingViolation, entryIndex, fmt.Sprintf("link[%v].type", i), "is required for podcast enclosures")
+		}
+		if link.Length <= 0 {
+			v.add(MissingViolation, entryIndex, fmt.Sprintf("link[%v].length", i), "is required for podcast enclosures")
+		}
+		return
+	}
+	v.add(MissingViolation, entryIndex, "link[rel=enclosure]", "is required for podcast episodes")
+}
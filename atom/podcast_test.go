@@ -0,0 +1,129 @@
+package atom
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func podcastRenderer() *HTMLRenderer {
+	settings := DefaultSettings()
+	settings.Podcast = &Podcast{
+		Image: &ITunesImage{Href: "podcast.png"},
+		Categories: []*ITunesCategory{
+			{Text: "Technology"},
+			{Text: "Arts", Subcategory: &ITunesCategory{Text: "Design"}},
+		},
+		Author: "Your Podcast",
+	}
+	return NewHTMLRenderer(settings)
+}
+
+func podcastHTMLEntries() []*HTMLEntry {
+	htmlEntries := defaultHTMLEntries()[0:2]
+	for i, htmlEntry := range htmlEntries {
+		htmlEntry.Enclosures = []*Enclosure{{URL: htmlEntry.ID + ".mp3", Type: "audio/mpeg", Length: int64(1000 * (i + 1))}}
+		htmlEntry.Episode = &PodcastEpisode{Duration: "32:10", Episode: i + 1, Season: 1}
+	}
+	return htmlEntries
+}
+
+func TestHTMLRenderer_Render_Podcast(t *testing.T) {
+	got, err := podcastRenderer().Render("podcast", "podcast.atom", "logo.png", podcastHTMLEntries())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixtureFilename := "podcast.xml"
+	if *updateFixturesPtr {
+		test.WriteFixture(t, fixtureFilename, got)
+		return
+	}
+	exp := test.ReadFixture(t, fixtureFilename)
+	if !bytes.Equal(got, exp) {
+		t.Error(test.NewContext().DiffString("HTMLRenderer.Render", string(got), string(exp), cmp.Diff(string(got), string(exp))))
+	}
+
+	feed, err := Parse(bytes.NewReader(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.AssertLabel(t, "Podcast.Image.Href", feed.Podcast.Image.Href, "https://yourwebsite.com/podcast.png")
+	test.AssertLabel(t, "Podcast.Categories[1].Subcategory.Text", feed.Podcast.Categories[1].Subcategory.Text, "Design")
+	test.AssertLabel(t, "Podcast.Author", feed.Podcast.Author, "Your Podcast")
+	test.AssertLabel(t, "Authors[0].Name", feed.Authors[0].Name, "Your Name")
+	test.AssertLabel(t, "Entries[1].Episode", feed.Entries[1].Episode, 2)
+	test.AssertLabel(t, "Entries[1].Links[1].Length", feed.Entries[1].Links[1].Length, int64(2000))
+}
+
+func TestHTMLRenderer_Render_NoPodcast(t *testing.T) {
+	got, err := defaultHTMLRenderer().Render("podcast", "podcast.atom", "logo.png", podcastHTMLEntries())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed, err := Parse(bytes.NewReader(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feed.Podcast != nil || feed.XMLNSITunes != "" || feed.Entries[0].PodcastEpisode != nil {
+		t.Errorf("podcast elements outputted without Settings.Podcast: %v, %v, %v", feed.Podcast, feed.XMLNSITunes, feed.Entries[0].PodcastEpisode)
+	}
+	test.AssertLabel(t, "Entries[0].Links[1].Rel", feed.Entries[0].Links[1].Rel, "enclosure")
+}
+
+func TestFeed_Validate_Podcast(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mutate func(feed *Feed)
+		exp    []violationKey
+	}{
+		{"valid", func(feed *Feed) {}, nil},
+		{"feed", func(feed *Feed) {
+			feed.Podcast.Image = nil
+			feed.Podcast.Categories = nil
+		}, []violationKey{
+			{MissingViolation, FeedIndex, "itunes:image"},
+			{MissingViolation, FeedIndex, "itunes:category"},
+		}},
+		{"relative image", func(feed *Feed) {
+			feed.Podcast.Image.Href = "podcast.png"
+		}, []violationKey{
+			{RelativeIRIViolation, FeedIndex, "itunes:image"},
+		}},
+		{"enclosures", func(feed *Feed) {
+			feed.Entries[0].Links[1].Type = ""
+			feed.Entries[0].Links[1].Length = 0
+			feed.Entries[1].Links = feed.Entries[1].Links[0:1]
+		}, []violationKey{
+			{MissingViolation, 0, "link[1].type"},
+			{MissingViolation, 0, "link[1].length"},
+			{MissingViolation, 1, "link[rel=enclosure]"},
+		}},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+			"name":  tc.name,
+		})
+
+		htmlEntries := podcastHTMLEntries()
+		for _, htmlEntry := range htmlEntries {
+			htmlEntry.Updated = htmlEntry.Published
+		}
+		feed := HTMLEntriesToFeed(NewRenderer(podcastRenderer().Settings), "podcast", "podcast.atom", "logo.png", htmlEntries)
+		tc.mutate(feed)
+
+		var got []violationKey
+		for _, violation := range feed.Validate() {
+			got = append(got, violationKey{violation.Kind, violation.EntryIndex, violation.Field})
+		}
+		if !cmp.Equal(got, tc.exp) {
+			t.Error(context.DiffString("Result", got, tc.exp, cmp.Diff(got, tc.exp)))
+		}
+	}
+}
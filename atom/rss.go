@@ -0,0 +1,181 @@
+package atom
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// RSSMediaType is the media type of RSS feeds
+const RSSMediaType = "application/rss+xml"
+
+// ContentNamespace is the XML namespace of the RSS content module, used for content:encoded
+const ContentNamespace = "http://purl.org/rss/1.0/modules/content/"
+
+// DCNamespace is the XML namespace of Dublin Core, used for dc:creator
+const DCNamespace = "http://purl.org/dc/elements/1.1/"
+
+// RSS represents the entire RSS 2.0 document
+type RSS struct {
+	XMLName xml.Name `xml:"rss"`
+
+	Version      string `xml:"version,attr"`
+	XMLNSAtom    string `xml:"xmlns:atom,attr"`
+	XMLNSContent string `xml:"xmlns:content,attr"`
+	XMLNSDC      string `xml:"xmlns:dc,attr"`
+
+	Channel *RSSChannel `xml:"channel"`
+}
+
+// Marhshall returns the RSS xml data of the document
+func (rss *RSS) Marhshall() ([]byte, error) {
+	bytes, err := xml.MarshalIndent(&rss, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), bytes...), nil
+}
+
+// RSSChannel represents the channel of the RSS document
+type RSSChannel struct {
+	XMLName xml.Name `xml:"channel"`
+
+	Title         string `xml:"title"`
+	Link          string `xml:"link"`
+	Description   string `xml:"description"`
+	Language      string `xml:"language"`
+	LastBuildDate string `xml:"lastBuildDate"`
+	// PubDate is the publication date of the channel, which is read from remote feeds, see Aggregator
+	PubDate string `xml:"pubDate,omitempty"`
+
+	AtomLink *RSSAtomLink `xml:"atom:link"`
+	Image    *RSSImage    `xml:"image"`
+
+	Items []*RSSItem `xml:"item"`
+}
+
+// RSSAtomLink represents the atom:link in the RSS channel, used for rel="self"
+type RSSAtomLink struct {
+	XMLName xml.Name `xml:"atom:link"`
+
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+	Href string `xml:"href,attr"`
+}
+
+// RSSImage represents the image of the RSS channel
+type RSSImage struct {
+	XMLName xml.Name `xml:"image"`
+
+	URL   string `xml:"url"`
+	Title string `xml:"title"`
+	Link  string `xml:"link"`
+}
+
+// RSSItem represents an item in the RSS channel
+type RSSItem struct {
+	XMLName xml.Name `xml:"item"`
+
+	Title    string   `xml:"title"`
+	Link     string   `xml:"link"`
+	GUID     *RSSGUID `xml:"guid"`
+	PubDate  string   `xml:"pubDate"`
+	Creators []string `xml:"dc:creator"`
+
+	Description    string             `xml:"description,omitempty"`
+	ContentEncoded *RSSContentEncoded `xml:"content:encoded"`
+}
+
+// RSSGUID represents the guid of a RSS item
+type RSSGUID struct {
+	XMLName xml.Name `xml:"guid"`
+
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	GUID        string `xml:",chardata"`
+}
+
+// RSSContentEncoded represents the HTML content of a RSS item
+type RSSContentEncoded struct {
+	XMLName xml.Name `xml:"content:encoded"`
+
+	Content string `xml:",cdata"`
+}
+
+// RSSDate formats the time in RFC 822 format (with a 4 digit year), as required by RSS
+func RSSDate(t time.Time) string {
+	return t.Format(time.RFC1123Z)
+}
+
+// ToRSSItem converts the HTMLEntry to a RSS item
+func (htmlEntry *HTMLEntry) ToRSSItem(a *Renderer) *RSSItem {
+	url := a.Settings.FullURLFor(htmlEntry.ID)
+	published := htmlEntry.Published
+	if published.IsZero() {
+		published = htmlEntry.Updated
+	}
+
+	return &RSSItem{
+		Title:    htmlEntry.Title,
+		Link:     url,
+		GUID:     &RSSGUID{IsPermaLink: true, GUID: url},
+		PubDate:  RSSDate(published),
+		Creators: authorNames(htmlEntry.authors(a)),
+
+		Description:    htmlEntry.Summary,
+		ContentEncoded: &RSSContentEncoded{Content: htmlEntry.HTMLContent},
+	}
+}
+
+func authorNames(authors []*Author) []string {
+	names := make([]string, len(authors))
+	for i, author := range authors {
+		names[i] = author.Name
+	}
+	return names
+}
+
+// RSSRenderer is a "main struct", which generates a RSS 2.0 document from the same inputs as HTMLRenderer
+type RSSRenderer struct {
+	Settings *Settings
+}
+
+// NewRSSRenderer returns a new instance of RSSRenderer
+func NewRSSRenderer(settings *Settings) *RSSRenderer {
+	return &RSSRenderer{settings}
+}
+
+// Render returns the RSS xml data of a generated document
+func (renderer *RSSRenderer) Render(feedName, selfURL, logoURL string, htmlEntries []*HTMLEntry) ([]byte, error) {
+	atomRenderer := NewRenderer(renderer.Settings)
+	rss := HTMLEntriesToRSS(atomRenderer, feedName, selfURL, logoURL, htmlEntries)
+	return rss.Marhshall()
+}
+
+// HTMLEntriesToRSS converts []HTMLEntry to a RSS document, with lots of defaults set
+func HTMLEntriesToRSS(atomRenderer *Renderer, feedName, selfURL, logoURL string, htmlEntries []*HTMLEntry) *RSS {
+	items := make([]*RSSItem, len(htmlEntries))
+	for i, htmlEntry := range htmlEntries {
+		items[i] = htmlEntry.ToRSSItem(atomRenderer)
+	}
+
+	settings := atomRenderer.Settings
+	title := atomRenderer.Title(feedName)
+	return &RSS{
+		Version:      "2.0",
+		XMLNSAtom:    Namespace,
+		XMLNSContent: ContentNamespace,
+		XMLNSDC:      DCNamespace,
+
+		Channel: &RSSChannel{
+			Title:         title,
+			Link:          settings.URL(),
+			Description:   title,
+			Language:      settings.FeedOptions.LanguageDefaulted(),
+			LastBuildDate: RSSDate(LastUpdated(settings, htmlEntries)),
+
+			AtomLink: &RSSAtomLink{Rel: "self", Type: RSSMediaType, Href: settings.FullURLFor(selfURL)},
+			Image:    &RSSImage{URL: settings.FullURLFor(logoURL), Title: title, Link: settings.URL()},
+
+			Items: items,
+		},
+	}
+}
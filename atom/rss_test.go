@@ -0,0 +1,58 @@
+package atom
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func defaultRSSRenderer() *RSSRenderer {
+	settings := DefaultSettings()
+	settings.Clock = testClock
+	return NewRSSRenderer(settings)
+}
+
+func TestRSSRenderer_Render(t *testing.T) {
+	htmlEntries := defaultHTMLEntries()
+	htmlEntries[2].Published = time.Time{}
+
+	testCases := []struct {
+		htmlEntries []*HTMLEntry
+	}{
+		{htmlEntries},
+		{htmlEntries[0:2]},
+		{htmlEntries[0:1]},
+		{[]*HTMLEntry{}},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+		})
+
+		bytes, err := defaultRSSRenderer().Render("the test feed for posts", "posts.rss", "logo.png", tc.htmlEntries)
+		if err != nil {
+			t.Error(context.String(err))
+		}
+
+		got := string(bytes)
+		fixtureFilename := fmt.Sprintf("rss%v.xml", testCaseIndex)
+		if *updateFixturesPtr {
+			test.WriteFixture(t, fixtureFilename, []byte(got))
+			continue
+		}
+
+		exp := string(test.ReadFixture(t, fixtureFilename))
+		if got != exp {
+			t.Error(context.DiffString("RSSRenderer.Render", got, exp, cmp.Diff(got, exp)))
+		}
+	}
+}
+
+func TestRSSDate(t *testing.T) {
+	test.AssertLabel(t, "Result", RSSDate(test.Time(2)), "Tue, 02 Jan 2018 02:02:02 +0000")
+}
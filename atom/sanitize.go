@@ -0,0 +1,198 @@
+package atom
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// voidElements are the HTML elements without end tags
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+// Policy is an allow-list of HTML elements and attributes, used by Sanitize
+type Policy struct {
+	// Elements maps the allowed elements to their allowed attributes.
+	// Elements that are not allowed are removed, but their content is kept.
+	Elements map[string][]string
+	// GlobalAttributes are the attributes allowed on every allowed element
+	GlobalAttributes []string
+	// DropElements are removed along with their content
+	DropElements []string
+	// URLSchemes are the allowed schemes of href, src, poster, cite and srcset attributes.
+	// Relative URLs are always allowed.
+	URLSchemes []string
+}
+
+// DefaultPolicy returns a Policy tuned for feed readers, which removes scripts, embeds, forms,
+// styles and event handlers
+func DefaultPolicy() *Policy {
+	return &Policy{
+		Elements: map[string][]string{
+			"a": {"href"}, "abbr": nil, "b": nil, "blockquote": {"cite"}, "br": nil, "caption": nil,
+			"cite": nil, "code": nil, "dd": nil, "del": {"cite", "datetime"}, "details": nil, "dfn": nil,
+			"div": nil, "dl": nil, "dt": nil, "em": nil, "figcaption": nil, "figure": nil,
+			"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil, "hr": nil, "i": nil,
+			"img": {"src", "srcset", "alt", "width", "height"}, "ins": {"cite", "datetime"}, "kbd": nil,
+			"li": nil, "mark": nil, "ol": {"start", "reversed"}, "p": nil, "pre": nil, "q": {"cite"},
+			"s": nil, "samp": nil, "small": nil, "span": nil, "strong": nil, "sub": nil, "summary": nil,
+			"sup": nil, "table": nil, "tbody": nil, "td": {"colspan", "rowspan"}, "tfoot": nil,
+			"th": {"colspan", "rowspan", "scope"}, "thead": nil, "time": {"datetime"}, "tr": nil,
+			"u": nil, "ul": nil, "var": nil,
+			"audio": {"src", "controls"}, "video": {"src", "poster", "controls", "width", "height"},
+			"source": {"src", "srcset", "type"},
+		},
+		GlobalAttributes: []string{"title", "lang", "dir"},
+		DropElements:     []string{"script", "style", "iframe", "object", "embed", "noscript", "template", "form"},
+		URLSchemes:       []string{"http", "https", "mailto"},
+	}
+}
+
+// Removal is an element or attribute removed by Sanitize
+type Removal struct {
+	Element string
+	// Attribute is the removed attribute of the Element, empty if the Element itself was removed
+	Attribute string
+}
+
+// String returns the string representation of the Removal: <script> or <a onclick>
+func (removal *Removal) String() string {
+	if removal.Attribute == "" {
+		return fmt.Sprintf("<%v>", removal.Element)
+	}
+	return fmt.Sprintf("<%v %v>", removal.Element, removal.Attribute)
+}
+
+// Sanitize returns the htmlContent with only the elements and attributes allowed by the Policy,
+// along with what was removed. Comments and doctypes are always removed.
+func (policy *Policy) Sanitize(htmlContent string) (string, []*Removal, error) {
+	s := &sanitizer{policy: policy}
+	tokenizer := html.NewTokenizer(strings.NewReader(htmlContent))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			if tokenizer.Err() == io.EOF {
+				return s.buffer.String(), s.removals, nil
+			}
+			return "", nil, tokenizer.Err()
+		}
+		s.token(tokenizer, tokenType)
+	}
+}
+
+func (policy *Policy) allowedAttribute(element, attribute string) bool {
+	return containsString(policy.Elements[element], attribute) || containsString(policy.GlobalAttributes, attribute)
+}
+
+func (policy *Policy) allowedURL(rawURL string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "" || containsString(policy.URLSchemes, strings.ToLower(u.Scheme))
+}
+
+func (policy *Policy) allowedAttributeValue(attr html.Attribute) bool {
+	switch attr.Key {
+	case "href", "src", "poster", "cite":
+		return policy.allowedURL(attr.Val)
+	case "srcset":
+		for _, candidate := range strings.Split(attr.Val, ",") {
+			fields := strings.Fields(candidate)
+			if len(fields) > 0 && !policy.allowedURL(fields[0]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type sanitizer struct {
+	policy   *Policy
+	buffer   bytes.Buffer
+	removals []*Removal
+
+	dropping  string
+	dropDepth int
+}
+
+func (s *sanitizer) remove(element, attribute string) {
+	s.removals = append(s.removals, &Removal{element, attribute})
+}
+
+func (s *sanitizer) token(tokenizer *html.Tokenizer, tokenType html.TokenType) {
+	if s.dropping != "" {
+		s.drop(tokenizer.Token(), tokenType)
+		return
+	}
+
+	switch tokenType {
+	case html.StartTagToken, html.SelfClosingTagToken:
+		s.startTag(tokenizer.Token(), tokenType)
+	case html.EndTagToken:
+		// void elements have no end tags, i.e. the <br></br> of XHTML would be two line breaks
+		token := tokenizer.Token()
+		if _, allowed := s.policy.Elements[token.Data]; allowed && !voidElements[token.Data] {
+			s.buffer.WriteString(token.String())
+		}
+	case html.TextToken:
+		// escape the text, so the contents of removed raw text elements, i.e. <textarea>, stay text
+		s.buffer.WriteString(html.EscapeString(tokenizer.Token().Data))
+	}
+}
+
+func (s *sanitizer) drop(token html.Token, tokenType html.TokenType) {
+	if token.Data != s.dropping {
+		return
+	}
+	switch tokenType {
+	case html.StartTagToken:
+		s.dropDepth++
+	case html.EndTagToken:
+		s.dropDepth--
+		if s.dropDepth == 0 {
+			s.dropping = ""
+		}
+	}
+}
+
+func (s *sanitizer) startTag(token html.Token, tokenType html.TokenType) {
+	if containsString(s.policy.DropElements, token.Data) {
+		s.remove(token.Data, "")
+		if tokenType == html.StartTagToken && !voidElements[token.Data] {
+			s.dropping = token.Data
+			s.dropDepth = 1
+		}
+		return
+	}
+	if _, allowed := s.policy.Elements[token.Data]; !allowed {
+		s.remove(token.Data, "")
+		return
+	}
+
+	attrs := make([]html.Attribute, 0, len(token.Attr))
+	for _, attr := range token.Attr {
+		if s.policy.allowedAttribute(token.Data, attr.Key) && s.policy.allowedAttributeValue(attr) {
+			attrs = append(attrs, attr)
+		} else {
+			s.remove(token.Data, attr.Key)
+		}
+	}
+	token.Attr = attrs
+	s.buffer.WriteString(token.String())
+}
+
+func containsString(slice []string, s string) bool {
+	for _, element := range slice {
+		if element == s {
+			return true
+		}
+	}
+	return false
+}
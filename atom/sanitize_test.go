@@ -0,0 +1,90 @@
+package atom
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func TestPolicy_Sanitize(t *testing.T) {
+	testCases := []struct {
+		htmlContent string
+		exp         string
+		expRemovals []string
+	}{
+		{`<p>Safe <em>text</em> &amp; more</p>`, `<p>Safe <em>text</em> &amp; more</p>`, nil},
+		{`<p>Demo</p><script>alert("<p>x</p>")</script><p>After</p>`, `<p>Demo</p><p>After</p>`, []string{"<script>"}},
+		{`<style>p { color: red }</style><p style="color: red" class="x">Red</p>`, `<p>Red</p>`, []string{"<style>", "<p style>", "<p class>"}},
+		{`<a href="/x" onclick="steal()">x</a>`, `<a href="/x">x</a>`, []string{"<a onclick>"}},
+		{`<a href="javascript:steal()" title="t">x</a>`, `<a title="t">x</a>`, []string{"<a href>"}},
+		{`<iframe src="https://demo.com"><p>fallback</p></iframe><p>kept</p>`, `<p>kept</p>`, []string{"<iframe>"}},
+		{`<form><form><input></form><p>gone</p></form><p>kept</p>`, `<p>kept</p>`, []string{"<form>"}},
+		{`<embed src="x.swf"><p>kept</p>`, `<p>kept</p>`, []string{"<embed>"}},
+		{`<center><p>unwrapped</p></center>`, `<p>unwrapped</p>`, []string{"<center>"}},
+		{`<!-- comment --><img src="a.png" srcset="b.png 2x" alt="a"/>`, `<img src="a.png" srcset="b.png 2x" alt="a"/>`, nil},
+		{`<p>a<br></br>b</p>`, `<p>a<br>b</p>`, nil},
+		{`<img src="a.png" srcset="b.png 1x, data:image/png;base64,xx 2x">`, `<img src="a.png">`, []string{"<img srcset>"}},
+		{`<textarea><script>alert(1)</script></textarea>`, `&lt;script&gt;alert(1)&lt;/script&gt;`, []string{"<textarea>"}},
+		{`<title><img src=x onerror=alert(1)></title>`, `&lt;img src=x onerror=alert(1)&gt;`, []string{"<title>"}},
+		{`<xmp><script>alert(1)</script></xmp>`, `&lt;script&gt;alert(1)&lt;/script&gt;`, []string{"<xmp>"}},
+		{`<noembed><img src=x onerror=alert(1)></noembed>`, `&lt;img src=x onerror=alert(1)&gt;`, []string{"<noembed>"}},
+		{`<noframes><script>alert(1)</script></noframes>`, `&lt;script&gt;alert(1)&lt;/script&gt;`, []string{"<noframes>"}},
+		{`<plaintext><script>alert(1)</script>`, `&lt;script&gt;alert(1)&lt;/script&gt;`, []string{"<plaintext>"}},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":       testCaseIndex,
+			"htmlContent": tc.htmlContent,
+		})
+
+		got, removals, err := DefaultPolicy().Sanitize(tc.htmlContent)
+		if err != nil {
+			t.Error(context.String(err))
+		}
+		if got != tc.exp {
+			t.Error(context.GotExpString("Result", got, tc.exp))
+		}
+
+		var gotRemovals []string
+		for _, removal := range removals {
+			gotRemovals = append(gotRemovals, removal.String())
+		}
+		if !cmp.Equal(gotRemovals, tc.expRemovals) {
+			t.Error(context.GotExpString("Removals", gotRemovals, tc.expRemovals))
+		}
+	}
+}
+
+func TestHTMLRenderer_SanitizeReport(t *testing.T) {
+	htmlEntries := defaultHTMLEntries()
+	htmlEntries[1].HTMLContent = `<p onclick="x()">Demo</p><script>demo()</script>`
+
+	renderer := defaultHTMLRenderer()
+	report, err := renderer.SanitizeReport(htmlEntries)
+	if err != nil {
+		t.Error(err)
+	}
+	test.AssertLabel(t, "len(report) without Policy", len(report), 0)
+
+	renderer.Policy = DefaultPolicy()
+	report, err = renderer.SanitizeReport(htmlEntries)
+	if err != nil {
+		t.Error(err)
+	}
+	exp := map[string][]*Removal{"second": {{"p", "onclick"}, {"script", ""}}}
+	if !cmp.Equal(report, exp) {
+		t.Errorf("got: %v, exp: %v", report, exp)
+	}
+
+	feed := HTMLEntriesToFeed(defaultRenderer(), "posts", "posts.atom", "logo.png", htmlEntries)
+	test.AssertLabel(t, "unsanitized content", feed.Entries[1].Content.Content, htmlEntries[1].HTMLContent)
+
+	processed, err := renderer.processEntries(htmlEntries)
+	if err != nil {
+		t.Error(err)
+	}
+	test.AssertLabel(t, "sanitized content", processed[1].HTMLContent, "<p>Demo</p>")
+}
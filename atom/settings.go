@@ -2,9 +2,21 @@ package atom
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
+	"time"
 )
 
+const (
+	// LegacyIDScheme generates IDs in the original host:2018:feedName and host:id:date format.
+	// They aren't valid URIs, but changing them makes feed readers show duplicate entries.
+	LegacyIDScheme = "legacy"
+	// TagIDScheme generates tag: URIs (RFC 4151), i.e. tag:host,2018-01-01:/posts
+	TagIDScheme = "tag"
+)
+
+const legacyIDDate = "2018"
+
 // Settings presents the settings of the Atom feed
 type Settings struct {
 	AuthorName string `json:"author_name,omitempty"`
@@ -12,15 +24,45 @@ type Settings struct {
 
 	Host string `json:"host,omitempty"`
 	SSL  bool   `json:"ssl,omitempty"`
+	// BasePath is the path the site is served from, i.e. "/blog" for example.com/blog. The IDs don't include it.
+	BasePath string `json:"base_path,omitempty"`
+
+	// IDScheme is LegacyIDScheme or TagIDScheme, defaults to LegacyIDScheme
+	IDScheme string `json:"id_scheme,omitempty"`
+	// IDDate is the date (YYYY, YYYY-MM or YYYY-MM-DD) you owned the Host, used by TagIDScheme
+	IDDate string `json:"id_date,omitempty"`
+
+	// Podcast adds the iTunes podcast elements to the feeds when set
+	Podcast *Podcast `json:"podcast,omitempty"`
+
+	// Hubs are the WebSub hub URLs advertised in the feeds and notified by Publisher
+	Hubs []string `json:"hubs,omitempty"`
+
+	// TombstoneDays is the number of days deleted entries are kept as tombstones, defaults to DefaultTombstoneDays
+	TombstoneDays int `json:"tombstone_days,omitempty"`
+
+	// FeedOptions are the optional metadata of the feeds
+	FeedOptions *FeedOptions `json:"feed_options,omitempty"`
+
+	// DiscoveryFeeds are the feeds advertised in templates by Discovery
+	DiscoveryFeeds []*DiscoveryFeed `json:"discovery_feeds,omitempty"`
+
+	// Aggregator configures the remote feeds combined by Aggregator
+	Aggregator *AggregatorSettings `json:"aggregator,omitempty"`
+
+	// Clock returns the current time, which is used when the time can't come from the entries.
+	// Defaults to time.Now.
+	Clock func() time.Time `json:"-"`
 }
 
 // DefaultSettings returns the default settings
 func DefaultSettings() *Settings {
 	return &Settings{
-		"Your Name",
-		"",
-		"yourwebsite.com",
-		true,
+		AuthorName:    "Your Name",
+		Host:          "yourwebsite.com",
+		SSL:           true,
+		IDScheme:      LegacyIDScheme,
+		TombstoneDays: DefaultTombstoneDays,
 	}
 }
 
@@ -32,20 +74,110 @@ func (settings *Settings) AuthorURIDefaulted() string {
 	return settings.AuthorURI
 }
 
-// URL returns the home page full URL of your site, including host, protocol, etc.
+// Now returns the current time from the Clock
+func (settings *Settings) Now() time.Time {
+	if settings.Clock == nil {
+		return time.Now()
+	}
+	return settings.Clock()
+}
+
+// TombstoneDaysDefaulted returns a defaulted TombstoneDays
+func (settings *Settings) TombstoneDaysDefaulted() int {
+	if settings.TombstoneDays <= 0 {
+		return DefaultTombstoneDays
+	}
+	return settings.TombstoneDays
+}
+
+// IDDateDefaulted returns a defaulted IDDate
+func (settings *Settings) IDDateDefaulted() string {
+	if settings.IDDate == "" {
+		return legacyIDDate
+	}
+	return settings.IDDate
+}
+
+// FeedID returns the ID of the feed with the given feedName
+func (settings *Settings) FeedID(feedName string) string {
+	if settings.IDScheme == TagIDScheme {
+		return settings.tagURI(feedName)
+	}
+	return strings.Join([]string{settings.Host, legacyIDDate, feedName}, ":")
+}
+
+// EntryID returns the ID of the entry at the given URL, which was published at the given time
+func (settings *Settings) EntryID(url string, published time.Time) string {
+	if settings.IDScheme == TagIDScheme {
+		return settings.tagURI(url)
+	}
+	return strings.Join([]string{settings.Host, url, published.Format("2006-01-02")}, ":")
+}
+
+func (settings *Settings) tagURI(specific string) string {
+	authority := strings.Split(settings.Host, ":")[0]
+	specific = (&url.URL{Path: "/" + strings.Trim(specific, "/")}).EscapedPath()
+	return fmt.Sprintf("tag:%v,%v:%v", authority, settings.IDDateDefaulted(), specific)
+}
+
+// URL returns the home page full URL of your site, including protocol, host and BasePath
 func (settings *Settings) URL() string {
-	ssl := ""
+	return settings.baseURL().String()
+}
+
+func (settings *Settings) baseURL() *url.URL {
+	scheme := "http"
 	if settings.SSL {
-		ssl = "s"
+		scheme = "https"
+	}
+	return &url.URL{Scheme: scheme, Host: settings.Host, Path: settings.basePath()}
+}
+
+// basePath returns the BasePath with a leading slash and without a trailing slash, or "" for the root
+func (settings *Settings) basePath() string {
+	basePath := strings.Trim(settings.BasePath, "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
+
+// FullURLFor gives the full URL for the given URL. Relative and root-relative URLs are under the BasePath
+// and keep their query and fragment: "/posts?page=2#top" => "https://example.com/blog/posts?page=2#top".
+// Their leading and trailing slashes are trimmed, so "posts/first/" => "https://example.com/blog/posts/first"
+// and "?page=2" => "https://example.com/blog/?page=2".
+// Absolute URLs are returned as is and scheme-relative URLs ("//cdn.com/image.png") get the scheme of the site.
+func (settings *Settings) FullURLFor(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return settings.joinURL(rawURL)
+	}
+
+	base := settings.baseURL()
+	switch {
+	case u.IsAbs():
+		return u.String()
+	case u.Host != "":
+		u.Scheme = base.Scheme
+		return u.String()
+	}
+
+	if relativePath := strings.Trim(u.Path, "/"); relativePath != "" {
+		base.RawPath = base.EscapedPath() + "/" + strings.Trim(u.EscapedPath(), "/")
+		base.Path += "/" + relativePath
+	} else if u.RawQuery != "" || u.Fragment != "" {
+		base.Path += "/"
 	}
-	return fmt.Sprintf("http%v://%v", ssl, settings.Host)
+	base.RawQuery = u.RawQuery
+	base.Fragment = u.Fragment
+	return base.String()
 }
 
-// FullURLFor gives the full URL for the given URL
-func (settings *Settings) FullURLFor(url string) string {
-	url = strings.Trim(url, "/")
-	if url == "" {
+// joinURL joins the path of rawURL to the URL, for URLs that can't be parsed
+func (settings *Settings) joinURL(rawURL string) string {
+	rawURL = strings.Trim(rawURL, "/")
+	if rawURL == "" {
 		return settings.URL()
 	}
-	return strings.Join([]string{settings.URL(), url}, "/")
+	return strings.Join([]string{settings.URL(), rawURL}, "/")
 }
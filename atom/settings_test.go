@@ -0,0 +1,163 @@
+package atom
+
+import (
+	"testing"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func TestSettings_FeedID(t *testing.T) {
+	testCases := []struct {
+		idScheme string
+		idDate   string
+		feedName string
+		exp      string
+	}{
+		{"", "", "posts", "yourwebsite.com:2018:posts"},
+		{LegacyIDScheme, "2016-05-01", "posts", "yourwebsite.com:2018:posts"},
+		{TagIDScheme, "", "posts", "tag:yourwebsite.com,2018:/posts"},
+		{TagIDScheme, "2016-05-01", "posts", "tag:yourwebsite.com,2016-05-01:/posts"},
+		{TagIDScheme, "2016-05", "the test feed for posts", "tag:yourwebsite.com,2016-05:/the%20test%20feed%20for%20posts"},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":    testCaseIndex,
+			"idScheme": tc.idScheme,
+			"idDate":   tc.idDate,
+		})
+
+		settings := DefaultSettings()
+		settings.IDScheme = tc.idScheme
+		settings.IDDate = tc.idDate
+
+		got := settings.FeedID(tc.feedName)
+		if got != tc.exp {
+			t.Error(context.GotExpString("Result", got, tc.exp))
+		}
+	}
+}
+
+func TestSettings_EntryID(t *testing.T) {
+	testCases := []struct {
+		idScheme string
+		host     string
+		url      string
+		exp      string
+	}{
+		{LegacyIDScheme, "yourwebsite.com", "first", "yourwebsite.com:first:2018-01-02"},
+		{LegacyIDScheme, "yourwebsite.com", "posts/first", "yourwebsite.com:posts/first:2018-01-02"},
+		{TagIDScheme, "yourwebsite.com", "first", "tag:yourwebsite.com,2016-05-01:/first"},
+		{TagIDScheme, "yourwebsite.com", "/posts/first/", "tag:yourwebsite.com,2016-05-01:/posts/first"},
+		{TagIDScheme, "yourwebsite.com:8080", "first", "tag:yourwebsite.com,2016-05-01:/first"},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":    testCaseIndex,
+			"idScheme": tc.idScheme,
+			"host":     tc.host,
+			"url":      tc.url,
+		})
+
+		settings := DefaultSettings()
+		settings.IDScheme = tc.idScheme
+		settings.IDDate = "2016-05-01"
+		settings.Host = tc.host
+
+		got := settings.EntryID(tc.url, test.Time(2))
+		if got != tc.exp {
+			t.Error(context.GotExpString("Result", got, tc.exp))
+		}
+	}
+}
+
+func TestSettings_URL(t *testing.T) {
+	testCases := []struct {
+		host     string
+		ssl      bool
+		basePath string
+		exp      string
+	}{
+		{"yourwebsite.com", true, "", "https://yourwebsite.com"},
+		{"yourwebsite.com", false, "", "http://yourwebsite.com"},
+		{"localhost:8080", false, "", "http://localhost:8080"},
+		{"yourwebsite.com", true, "/", "https://yourwebsite.com"},
+		{"yourwebsite.com", true, "blog", "https://yourwebsite.com/blog"},
+		{"yourwebsite.com", true, "/blog/", "https://yourwebsite.com/blog"},
+		{"yourwebsite.com:8443", true, "/my blog", "https://yourwebsite.com:8443/my%20blog"},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":    testCaseIndex,
+			"host":     tc.host,
+			"ssl":      tc.ssl,
+			"basePath": tc.basePath,
+		})
+
+		settings := DefaultSettings()
+		settings.Host = tc.host
+		settings.SSL = tc.ssl
+		settings.BasePath = tc.basePath
+
+		got := settings.URL()
+		if got != tc.exp {
+			t.Error(context.GotExpString("Result", got, tc.exp))
+		}
+	}
+}
+
+func TestSettings_FullURLFor(t *testing.T) {
+	testCases := []struct {
+		host     string
+		basePath string
+		url      string
+		exp      string
+	}{
+		{"yourwebsite.com", "", "", "https://yourwebsite.com"},
+		{"yourwebsite.com", "", "/", "https://yourwebsite.com"},
+		{"yourwebsite.com", "", "posts.atom", "https://yourwebsite.com/posts.atom"},
+		{"yourwebsite.com", "", "/posts.atom", "https://yourwebsite.com/posts.atom"},
+		{"yourwebsite.com", "", "posts/first/", "https://yourwebsite.com/posts/first"},
+		{"yourwebsite.com", "", " /posts ", "https://yourwebsite.com/posts"},
+		{"yourwebsite.com", "", "tags/go%20lang.atom", "https://yourwebsite.com/tags/go%20lang.atom"},
+		{"yourwebsite.com", "", "a%2Fb", "https://yourwebsite.com/a%2Fb"},
+		{"yourwebsite.com", "", "posts?page=2", "https://yourwebsite.com/posts?page=2"},
+		{"yourwebsite.com", "", "/posts#comments", "https://yourwebsite.com/posts#comments"},
+		{"yourwebsite.com", "", "?page=2", "https://yourwebsite.com/?page=2"},
+		{"yourwebsite.com", "", "/#top", "https://yourwebsite.com/#top"},
+		{"yourwebsite.com", "", "https://other.com/image.png", "https://other.com/image.png"},
+		{"yourwebsite.com", "", "http://other.com/a?b=c#d", "http://other.com/a?b=c#d"},
+		{"yourwebsite.com", "", "mailto:me@yourwebsite.com", "mailto:me@yourwebsite.com"},
+		{"yourwebsite.com", "", "//cdn.com/image.png", "https://cdn.com/image.png"},
+		{"yourwebsite.com:8080", "", "posts", "https://yourwebsite.com:8080/posts"},
+		{"yourwebsite.com", "/blog", "", "https://yourwebsite.com/blog"},
+		{"yourwebsite.com", "/blog", "posts.atom", "https://yourwebsite.com/blog/posts.atom"},
+		{"yourwebsite.com", "blog/", "/posts.atom", "https://yourwebsite.com/blog/posts.atom"},
+		{"yourwebsite.com", "/blog", "/posts?page=2#top", "https://yourwebsite.com/blog/posts?page=2#top"},
+		{"yourwebsite.com", "/blog", "posts/first/", "https://yourwebsite.com/blog/posts/first"},
+		{"yourwebsite.com", "/blog", "?page=2", "https://yourwebsite.com/blog/?page=2"},
+		{"yourwebsite.com:8080", "/blog", "images/a b.png", "https://yourwebsite.com:8080/blog/images/a%20b.png"},
+		{"yourwebsite.com", "/blog", "https://yourwebsite.com/elsewhere", "https://yourwebsite.com/elsewhere"},
+		{"yourwebsite.com", "/blog", "%zz", "https://yourwebsite.com/blog/%zz"},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":    testCaseIndex,
+			"host":     tc.host,
+			"basePath": tc.basePath,
+			"url":      tc.url,
+		})
+
+		settings := DefaultSettings()
+		settings.Host = tc.host
+		settings.BasePath = tc.basePath
+
+		got := settings.FullURLFor(tc.url)
+		if got != tc.exp {
+			t.Error(context.GotExpString("Result", got, tc.exp))
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package atom
+
+// StylesheetMediaType is the media type to serve DefaultStylesheet with
+const StylesheetMediaType = "text/xsl"
+
+// DefaultStylesheet is a XSLT stylesheet that shows an Atom feed as a HTML page, with the entries and
+// instructions for subscribing. Serve it from the same origin as the feeds, browsers don't load cross-origin
+// stylesheets, and set its href in Feed.Stylesheet or HTMLRenderer.Stylesheet.
+const DefaultStylesheet = `<?xml version="1.0" encoding="UTF-8"?>
+<xsl:stylesheet version="1.0"
+  xmlns:xsl="http://www.w3.org/1999/XSL/Transform"
+  xmlns:atom="http://www.w3.org/2005/Atom"
+  exclude-result-prefixes="atom">
+  <xsl:output method="html" encoding="UTF-8" indent="yes" doctype-system="about:legacy-compat"/>
+
+  <xsl:template match="/atom:feed">
+    <html>
+      <xsl:attribute name="lang"><xsl:value-of select="@xml:lang"/></xsl:attribute>
+      <head>
+        <meta charset="UTF-8"/>
+        <meta name="viewport" content="width=device-width, initial-scale=1"/>
+        <title><xsl:value-of select="atom:title"/> (Feed)</title>
+        <style>
+          body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.5; color: #222; max-width: 42rem; margin: 0 auto; padding: 1rem; }
+          .subscribe { background: #fff8e1; border: 1px solid #ffe082; border-radius: 4px; padding: 0.5rem 1rem; }
+          .subscribe code { word-break: break-all; }
+          .entry { border-bottom: 1px solid #eee; padding: 0.5rem 0; }
+          .entry h2 { font-size: 1.2rem; margin: 0.5rem 0 0; }
+          .date { color: #666; font-size: 0.9rem; }
+        </style>
+      </head>
+      <body>
+        <div class="subscribe">
+          <p><strong>This is a web feed.</strong> Subscribe by copying the URL below into your feed reader.</p>
+          <p><code><xsl:value-of select="atom:link[@rel='self']/@href"/></code></p>
+          <p>Visit <a href="https://aboutfeeds.com">About Feeds</a> to learn more and get started. It's free.</p>
+        </div>
+        <h1>
+          <a>
+            <xsl:attribute name="href"><xsl:value-of select="atom:link[@rel='alternate']/@href"/></xsl:attribute>
+            <xsl:value-of select="atom:title"/>
+          </a>
+        </h1>
+        <xsl:apply-templates select="atom:entry"/>
+      </body>
+    </html>
+  </xsl:template>
+
+  <xsl:template match="atom:entry">
+    <div class="entry">
+      <h2>
+        <a>
+          <xsl:attribute name="href"><xsl:value-of select="atom:link[@rel='alternate']/@href"/></xsl:attribute>
+          <xsl:value-of select="atom:title"/>
+        </a>
+      </h2>
+      <div class="date">
+        <xsl:choose>
+          <xsl:when test="atom:published"><xsl:value-of select="substring(atom:published, 1, 10)"/></xsl:when>
+          <xsl:otherwise><xsl:value-of select="substring(atom:updated, 1, 10)"/></xsl:otherwise>
+        </xsl:choose>
+      </div>
+      <xsl:if test="atom:summary">
+        <p><xsl:value-of select="atom:summary"/></p>
+      </xsl:if>
+    </div>
+  </xsl:template>
+</xsl:stylesheet>
+`
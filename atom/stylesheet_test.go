@@ -0,0 +1,62 @@
+package atom
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func TestHtmlRenderer_Render_Stylesheet(t *testing.T) {
+	renderer := defaultHTMLRenderer()
+	renderer.Stylesheet = "/feed.xsl?theme=light&lang=en"
+
+	bytes, err := renderer.Render("posts", "posts.atom", "logo.png", defaultHTMLEntries()[0:1])
+	if err != nil {
+		t.Error(err)
+	}
+
+	got := string(bytes)
+	fixtureFilename := "stylesheet.xml"
+	if *updateFixturesPtr {
+		test.WriteFixture(t, fixtureFilename, []byte(got))
+		return
+	}
+
+	exp := string(test.ReadFixture(t, fixtureFilename))
+	if got != exp {
+		t.Error(test.NewContext().DiffString("HTMLRenderer.Render", got, exp, cmp.Diff(got, exp)))
+	}
+
+	feed, err := Parse(strings.NewReader(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.AssertLabel(t, "Parse(...).Stylesheet", feed.Stylesheet, renderer.Stylesheet)
+}
+
+func TestDefaultStylesheet(t *testing.T) {
+	decoder := xml.NewDecoder(strings.NewReader(DefaultStylesheet))
+	namespaces := map[string]string{}
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "stylesheet" {
+			for _, attr := range start.Attr {
+				if attr.Name.Space == "xmlns" {
+					namespaces[attr.Name.Local] = attr.Value
+				}
+			}
+		}
+	}
+	test.AssertLabel(t, "xmlns:atom", namespaces["atom"], Namespace)
+}
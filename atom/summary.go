@@ -0,0 +1,111 @@
+package atom
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// SummaryLength is the recommended max length of a generated summary, see HTMLRenderer.SummaryLength
+const SummaryLength = 280
+
+// MoreMarker is the HTML comment in HTMLEntry.HTMLContent marking the end of the summary
+const MoreMarker = "<!--more-->"
+
+// textlessElements are the elements with content that isn't text
+var textlessElements = map[string]bool{
+	"script": true, "style": true, "template": true, "noscript": true,
+}
+
+// blockElements are the elements that separate words without whitespace around them
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "br": true, "dd": true, "div": true,
+	"dl": true, "dt": true, "figcaption": true, "figure": true, "footer": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "header": true, "hr": true, "li": true, "ol": true,
+	"p": true, "pre": true, "section": true, "table": true, "td": true, "th": true, "tr": true, "ul": true,
+}
+
+// HTMLToText returns the plain text of htmlContent, with the whitespace collapsed
+func HTMLToText(htmlContent string) string {
+	text, _ := htmlToText(htmlContent, false)
+	return text
+}
+
+// Summarize returns a plain text summary of htmlContent. It's the text before the MoreMarker if there is one,
+// otherwise all the text. Either way, it's truncated at a word boundary to at most length characters followed by "…".
+func Summarize(htmlContent string, length int) string {
+	text, _ := htmlToText(htmlContent, true)
+	return truncateWords(text, length)
+}
+
+// htmlToText returns the plain text of htmlContent, and if it stopped at the MoreMarker
+func htmlToText(htmlContent string, stopAtMore bool) (string, bool) {
+	var parts []string
+	skipping := ""
+	tokenizer := html.NewTokenizer(strings.NewReader(htmlContent))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			return collapseWhitespace(parts), false
+		}
+
+		token := tokenizer.Token()
+		if skipping != "" {
+			if tokenType == html.EndTagToken && token.Data == skipping {
+				skipping = ""
+			}
+			continue
+		}
+		if stopAtMore && isMoreMarker(tokenType, token) {
+			return collapseWhitespace(parts), true
+		}
+		if tokenType == html.StartTagToken && textlessElements[token.Data] {
+			skipping = token.Data
+			continue
+		}
+		parts = append(parts, tokenText(tokenType, token))
+	}
+}
+
+func isMoreMarker(tokenType html.TokenType, token html.Token) bool {
+	return tokenType == html.CommentToken && strings.TrimSpace(token.Data) == "more"
+}
+
+func tokenText(tokenType html.TokenType, token html.Token) string {
+	switch tokenType {
+	case html.TextToken:
+		return token.Data
+	case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+		if blockElements[token.Data] {
+			return " "
+		}
+	}
+	return ""
+}
+
+func collapseWhitespace(parts []string) string {
+	return strings.Join(strings.Fields(strings.Join(parts, "")), " ")
+}
+
+func truncateWords(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	if length < 1 {
+		return ""
+	}
+
+	// leave room for the "…"
+	end := length - 1
+	for end > 0 && !unicode.IsSpace(runes[end]) {
+		end--
+	}
+	if end == 0 {
+		end = length - 1
+	}
+	return strings.TrimRightFunc(string(runes[:end]), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "…"
+}
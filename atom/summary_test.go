@@ -0,0 +1,114 @@
+package atom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func TestHTMLToText(t *testing.T) {
+	testCases := []struct {
+		htmlContent string
+		exp         string
+	}{
+		{"", ""},
+		{"<p>The story starts here</p>", "The story starts here"},
+		{"<h1>Title</h1><p>First &amp; <em>second</em></p><p>Third<br>line</p>", "Title First & second Third line"},
+		{"<p>Demo</p><script>var x = '<p>no</p>';</script><style>p {}</style><p>After</p>", "Demo After"},
+		{"  <ul>\n<li>one</li>\n<li>two</li></ul>  ", "one two"},
+		{"<p>Before</p><!--more--><p>After</p>", "Before After"},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":       testCaseIndex,
+			"htmlContent": tc.htmlContent,
+		})
+
+		got := HTMLToText(tc.htmlContent)
+		if got != tc.exp {
+			t.Error(context.GotExpString("Result", got, tc.exp))
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	testCases := []struct {
+		htmlContent string
+		length      int
+		exp         string
+	}{
+		{"<p>The story starts here</p>", 100, "The story starts here"},
+		{"<p>The story starts here</p>", 21, "The story starts here"},
+		{"<p>The story starts here</p>", 20, "The story starts…"},
+		{"<p>The story, starts here</p>", 13, "The story…"},
+		{"<p>Supercalifragilistic</p>", 6, "Super…"},
+		{"<p>Über straße</p>", 8, "Über…"},
+		{"<p>The intro.</p><!-- more --><p>The story starts here</p>", 100, "The intro."},
+		{"<p>The intro.</p><!-- more --><p>The story starts here</p>", 5, "The…"},
+		{"<img src=\"a.png\">", 100, ""},
+		{"<p>The story</p>", 0, ""},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":       testCaseIndex,
+			"htmlContent": tc.htmlContent,
+			"length":      tc.length,
+		})
+
+		got := Summarize(tc.htmlContent, tc.length)
+		if got != tc.exp {
+			t.Error(context.GotExpString("Result", got, tc.exp))
+		}
+		if len([]rune(got)) > tc.length {
+			t.Error(context.GotExpString("len(Result)", len([]rune(got)), tc.length))
+		}
+	}
+}
+
+func TestHTMLRenderer_Render_Summary(t *testing.T) {
+	htmlEntries := defaultHTMLEntries()[0:2]
+	htmlEntries[0].Summary = ""
+	htmlEntries[1].Summary = ""
+	htmlEntries[1].HTMLContent = "<img src=\"a.png\">"
+
+	testCases := []struct {
+		summaryLength int
+		exp           []string
+	}{
+		{SummaryLength, []string{"The story starts here", ""}},
+		{10, []string{"The story…", ""}},
+		{0, []string{"", ""}},
+	}
+
+	test.AssertLabel(t, "NewHTMLRenderer.SummaryLength", defaultHTMLRenderer().SummaryLength, 0)
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":         testCaseIndex,
+			"summaryLength": tc.summaryLength,
+		})
+
+		renderer := defaultHTMLRenderer()
+		renderer.SummaryLength = tc.summaryLength
+		bytes, err := renderer.Render("posts", "posts.atom", "logo.png", htmlEntries)
+		if err != nil {
+			t.Error(context.String(err))
+		}
+		if strings.Contains(string(bytes), "<summary></summary>") {
+			t.Error(context.String("contains empty <summary></summary>"))
+		}
+
+		feed, err := Parse(strings.NewReader(string(bytes)))
+		if err != nil {
+			t.Error(context.String(err))
+			continue
+		}
+		for i, entry := range feed.Entries {
+			if entry.Summary != tc.exp[i] {
+				t.Error(context.GotExpString("Summary", entry.Summary, tc.exp[i]))
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package atom
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+)
+
+// TagURL returns the URL of the feed of the given tag, next to the feed at selfURL: "/posts.atom" => "/tags/go.atom"
+func TagURL(selfURL, tag string) string {
+	return path.Join(path.Dir(selfURL), "tags", url.PathEscape(tag)+path.Ext(selfURL))
+}
+
+// RenderTags renders the main feed and a feed for each tag in HTMLEntry.Tags, where each tag feed is at
+// TagURL(selfURL, tag). It returns a map of tag to the Atom xml data of the feed, where the main feed's key is "".
+func (renderer *HTMLRenderer) RenderTags(feedName, selfURL, logoURL string, htmlEntries []*HTMLEntry) (map[string][]byte, error) {
+	htmlEntries, err := renderer.processEntries(htmlEntries)
+	if err != nil {
+		return nil, err
+	}
+	atomRenderer := renderer.atomRenderer()
+
+	feeds := map[string]*Feed{
+		"": HTMLEntriesToFeed(atomRenderer, feedName, selfURL, logoURL, htmlEntries),
+	}
+	for tag, tagEntries := range TaggedHTMLEntries(htmlEntries) {
+		feed := HTMLEntriesToFeed(atomRenderer, fmt.Sprintf("%v tagged %v", feedName, tag), TagURL(selfURL, tag), logoURL, tagEntries)
+		feed.Categories = append(feed.Categories, &Category{Term: tag})
+		feeds[tag] = feed
+	}
+
+	tagFeeds := make(map[string][]byte, len(feeds))
+	for tag, feed := range feeds {
+		bytes, err := renderer.marshall(feed)
+		if err != nil {
+			return nil, err
+		}
+		tagFeeds[tag] = bytes
+	}
+	return tagFeeds, nil
+}
+
+// TaggedHTMLEntries returns a map of tag to the htmlEntries with the tag, keeping the order of htmlEntries
+func TaggedHTMLEntries(htmlEntries []*HTMLEntry) map[string][]*HTMLEntry {
+	tagged := map[string][]*HTMLEntry{}
+	for _, htmlEntry := range htmlEntries {
+		seen := map[string]bool{}
+		for _, tag := range htmlEntry.Tags {
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			tagged[tag] = append(tagged[tag], htmlEntry)
+		}
+	}
+	return tagged
+}
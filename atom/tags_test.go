@@ -0,0 +1,122 @@
+package atom
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func TestTagURL(t *testing.T) {
+	testCases := []struct {
+		selfURL string
+		tag     string
+		exp     string
+	}{
+		{"/posts.atom", "go", "/tags/go.atom"},
+		{"posts.atom", "go", "tags/go.atom"},
+		{"/blog/posts.xml", "static sites", "/blog/tags/static%20sites.xml"},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":   testCaseIndex,
+			"selfURL": tc.selfURL,
+			"tag":     tc.tag,
+		})
+
+		got := TagURL(tc.selfURL, tc.tag)
+		if got != tc.exp {
+			t.Error(context.GotExpString("Result", got, tc.exp))
+		}
+	}
+}
+
+func TestTagsToCategories(t *testing.T) {
+	testCases := []struct {
+		tags []string
+		exp  []string
+	}{
+		{nil, nil},
+		{[]string{"go"}, []string{"go"}},
+		{[]string{"go", "static", "go"}, []string{"go", "static"}},
+		{[]string{"", "static", "static", ""}, []string{"static"}},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+			"tags":  tc.tags,
+		})
+
+		var got []string
+		for _, category := range tagsToCategories(tc.tags) {
+			got = append(got, category.Term)
+		}
+		if !cmp.Equal(got, tc.exp) {
+			t.Error(context.GotExpString("Terms", got, tc.exp))
+		}
+	}
+}
+
+func taggedHTMLEntries() []*HTMLEntry {
+	htmlEntries := defaultHTMLEntries()
+	htmlEntries[0].Tags = []string{"go", "static"}
+	htmlEntries[1].Tags = []string{"static", "static"}
+	htmlEntries[2].Tags = []string{"go"}
+	return htmlEntries
+}
+
+func TestHTMLRenderer_RenderTags(t *testing.T) {
+	tagFeeds, err := defaultHTMLRenderer().RenderTags("posts", "/posts.atom", "logo.png", taggedHTMLEntries())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expTitles := map[string][]string{
+		"":       {"num #1", "num #2", "num #3"},
+		"go":     {"num #1", "num #3"},
+		"static": {"num #1", "num #2"},
+	}
+	var gotTags []string
+	for tag := range tagFeeds {
+		gotTags = append(gotTags, tag)
+	}
+	sort.Strings(gotTags)
+	if !cmp.Equal(gotTags, []string{"", "go", "static"}) {
+		t.Errorf("got: %v, exp: %v", gotTags, []string{"", "go", "static"})
+	}
+
+	for tag, exp := range expTitles {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"tag": tag,
+		})
+
+		feed, err := Parse(bytes.NewReader(tagFeeds[tag]))
+		if err != nil {
+			t.Error(context.String(err))
+			continue
+		}
+		got := make([]string, len(feed.Entries))
+		for i, entry := range feed.Entries {
+			got[i] = entry.Title
+		}
+		if !cmp.Equal(got, exp) {
+			t.Error(context.GotExpString("Entry titles", got, exp))
+		}
+	}
+
+	fixtureFilename := "tags_go.xml"
+	if *updateFixturesPtr {
+		test.WriteFixture(t, fixtureFilename, tagFeeds["go"])
+		return
+	}
+	got := string(tagFeeds["go"])
+	exp := string(test.ReadFixture(t, fixtureFilename))
+	if got != exp {
+		t.Error(test.NewContext().DiffString("tagFeeds[\"go\"]", got, exp, cmp.Diff(got, exp)))
+	}
+}
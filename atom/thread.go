@@ -0,0 +1,126 @@
+package atom
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// ThreadNamespace is the XML namespace of Atom Threading Extensions (RFC 4685)
+const ThreadNamespace = "http://purl.org/syndication/thread/1.0"
+
+// InReplyTo represents the thr:in-reply-to of an Entry, which is the resource the Entry replies to (RFC 4685)
+type InReplyTo struct {
+	XMLName xml.Name `xml:"thr:in-reply-to"`
+
+	// Ref is the ID of the resource replied to
+	Ref    string `xml:"ref,attr"`
+	Href   string `xml:"href,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Source string `xml:"source,attr,omitempty"`
+}
+
+// Replies are the replies to a HTMLEntry, which are in the feed at URL
+type Replies struct {
+	URL     string
+	Count   int
+	Updated time.Time
+}
+
+// NewReplies returns the Replies of the htmlEntries replying in the feed at url
+func NewReplies(url string, htmlEntries []*HTMLEntry) *Replies {
+	replies := &Replies{URL: url, Count: len(htmlEntries)}
+	for _, htmlEntry := range htmlEntries {
+		if htmlEntry.Updated.After(replies.Updated) {
+			replies.Updated = htmlEntry.Updated
+		}
+	}
+	return replies
+}
+
+// RepliesLink returns the rel="replies" Link of the replies, with thr:count and thr:updated
+func (a *Renderer) RepliesLink(replies *Replies) *Link {
+	count := replies.Count
+	link := &Link{Rel: "replies", Type: AtomMediaType, Href: a.Settings.FullURLFor(replies.URL), ThreadCount: &count}
+	if replies.Count > 0 {
+		updated := replies.Updated
+		link.ThreadUpdated = &updated
+	}
+	return link
+}
+
+// InReplyTo returns the thr:in-reply-to of a reply to the htmlEntry
+func (a *Renderer) InReplyTo(htmlEntry *HTMLEntry) *InReplyTo {
+	return &InReplyTo{
+		Ref:  a.Settings.EntryID(htmlEntry.ID, htmlEntry.Published),
+		Href: a.Settings.FullURLFor(htmlEntry.ID),
+		Type: "text/html",
+	}
+}
+
+func (htmlEntry *HTMLEntry) inReplyTo(a *Renderer) []*InReplyTo {
+	if htmlEntry.InReplyTo == nil {
+		return nil
+	}
+	return []*InReplyTo{a.InReplyTo(htmlEntry.InReplyTo)}
+}
+
+// threaded returns true if the entry has thr:in-reply-to elements or links with thr attributes
+func (entry *Entry) threaded() bool {
+	if len(entry.InReplyTo) > 0 {
+		return true
+	}
+	for _, link := range entry.Links {
+		if link.ThreadCount != nil || link.ThreadUpdated != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func threaded(htmlEntries []*HTMLEntry) bool {
+	for _, htmlEntry := range htmlEntries {
+		if htmlEntry.InReplyTo != nil || htmlEntry.Replies != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderComments renders the comments feed of the post at selfURL, where the comments are HTMLEntry replies.
+// Comments without a HTMLEntry.InReplyTo reply to the post, others reply to other comments.
+func (renderer *HTMLRenderer) RenderComments(post *HTMLEntry, selfURL, logoURL string, comments []*HTMLEntry) ([]byte, error) {
+	replies := make([]*HTMLEntry, len(comments))
+	for i, comment := range comments {
+		copied := *comment
+		if copied.InReplyTo == nil {
+			copied.InReplyTo = post
+		}
+		replies[i] = &copied
+	}
+
+	replies, err := renderer.processEntries(replies)
+	if err != nil {
+		return nil, err
+	}
+	atomRenderer := renderer.atomRenderer()
+	feed := HTMLEntriesToFeed(atomRenderer, post.Title, selfURL, logoURL, replies)
+	feed.ID = atomRenderer.Settings.FeedID(selfURL)
+	feed.Title = fmt.Sprintf("Comments on %v", post.Title)
+	for i, link := range feed.Links {
+		if link.Rel == "alternate" {
+			feed.Links[i] = atomRenderer.AlternateLink(post.ID)
+		}
+	}
+	return renderer.marshall(feed)
+}
+
+func (v *validator) inReplyTo(entryIndex int, inReplyTos []*InReplyTo) {
+	for i, inReplyTo := range inReplyTos {
+		field := fmt.Sprintf("thr:in-reply-to[%v]", i)
+		v.required(entryIndex, field+".ref", inReplyTo.Ref)
+		if inReplyTo.Href != "" {
+			v.iri(entryIndex, field+".href", inReplyTo.Href)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package atom
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func defaultComments() []*HTMLEntry {
+	comments := []*HTMLEntry{
+		{ID: "first#comment-1", Title: "Great post", Updated: test.Time(3), HTMLContent: "<p>I liked it</p>", Published: test.Time(3),
+			Authors: []*Author{{Name: "Reader"}}},
+		{ID: "first#comment-2", Title: "Re: Great post", Updated: test.Time(5), HTMLContent: "<p>Me too</p>", Published: test.Time(4),
+			Authors: []*Author{{Name: "Another Reader", URI: "https://another.com"}}},
+	}
+	comments[1].InReplyTo = comments[0]
+	return comments
+}
+
+func TestHtmlRenderer_RenderComments(t *testing.T) {
+	post := defaultHTMLEntries()[0]
+	comments := defaultComments()
+
+	testCases := []struct {
+		comments []*HTMLEntry
+	}{
+		{comments},
+		{[]*HTMLEntry{}},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+		})
+
+		bytes, err := defaultHTMLRenderer().RenderComments(post, "first/comments.atom", "logo.png", tc.comments)
+		if err != nil {
+			t.Error(context.String(err))
+		}
+
+		got := string(bytes)
+		fixtureFilename := fmt.Sprintf("comments%v.xml", testCaseIndex)
+		if *updateFixturesPtr {
+			test.WriteFixture(t, fixtureFilename, []byte(got))
+			continue
+		}
+
+		exp := string(test.ReadFixture(t, fixtureFilename))
+		if got != exp {
+			t.Error(context.DiffString("HTMLRenderer.RenderComments", got, exp, cmp.Diff(got, exp)))
+		}
+	}
+
+	if comments[0].InReplyTo != nil {
+		t.Error("RenderComments changed the comments")
+	}
+}
+
+func TestHtmlRenderer_Render_Replies(t *testing.T) {
+	htmlEntries := defaultHTMLEntries()
+	htmlEntries[0].Replies = NewReplies("first/comments.atom", defaultComments())
+	htmlEntries[1].Replies = NewReplies("second/comments.atom", nil)
+
+	bytes, err := defaultHTMLRenderer().Render("posts", "posts.atom", "logo.png", htmlEntries)
+	if err != nil {
+		t.Error(err)
+	}
+
+	got := string(bytes)
+	fixtureFilename := "replies.xml"
+	if *updateFixturesPtr {
+		test.WriteFixture(t, fixtureFilename, []byte(got))
+		return
+	}
+
+	exp := string(test.ReadFixture(t, fixtureFilename))
+	if got != exp {
+		t.Error(test.NewContext().DiffString("HTMLRenderer.Render", got, exp, cmp.Diff(got, exp)))
+	}
+}
+
+func TestNewReplies(t *testing.T) {
+	replies := NewReplies("first/comments.atom", defaultComments())
+	test.AssertLabel(t, "Count", replies.Count, 2)
+	test.AssertLabel(t, "Updated", replies.Updated, test.Time(5))
+
+	replies = NewReplies("first/comments.atom", nil)
+	test.AssertLabel(t, "Count", replies.Count, 0)
+	test.AssertLabel(t, "Updated.IsZero()", replies.Updated.IsZero(), true)
+}
+
+func TestFeed_Validate_InReplyTo(t *testing.T) {
+	feed := validFeed()
+	feed.Entries[0].InReplyTo = []*InReplyTo{{Ref: "tag:site.com,2018:/post", Href: "https://site.com/post"}, {Href: "post"}}
+
+	var got []string
+	for _, violation := range feed.Validate() {
+		got = append(got, violation.Error())
+	}
+	exp := []string{
+		"entry[0].thr:in-reply-to[1].ref: is required",
+		`entry[0].thr:in-reply-to[1].href: "post" is not an absolute IRI`,
+	}
+	if !cmp.Equal(got, exp) {
+		t.Error(test.NewContext().DiffString("Validate", got, exp, cmp.Diff(got, exp)))
+	}
+}
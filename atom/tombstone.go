@@ -0,0 +1,119 @@
+package atom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// TombstonesNamespace is the XML namespace of Atom Tombstones (RFC 6721)
+const TombstonesNamespace = "http://purl.org/atompub/tombstones/1.0"
+
+// DefaultTombstoneDays is the default number of days deleted entries are kept as tombstones
+const DefaultTombstoneDays = 30
+
+// DeletedEntry represents an at:deleted-entry tombstone of a deleted Entry (RFC 6721)
+type DeletedEntry struct {
+	XMLName xml.Name `xml:"at:deleted-entry"`
+
+	// Ref is the ID of the deleted Entry
+	Ref  string    `xml:"ref,attr"`
+	When time.Time `xml:"when,attr"`
+
+	Comment string  `xml:"at:comment,omitempty"`
+	Links   []*Link `xml:"link"`
+}
+
+// SetDeletedEntries sets the DeletedEntries of the feed and declares their namespace
+func (feed *Feed) SetDeletedEntries(deletedEntries []*DeletedEntry) {
+	feed.DeletedEntries = deletedEntries
+	if len(deletedEntries) == 0 {
+		feed.XMLNSAT = ""
+		return
+	}
+	feed.XMLNSAT = TombstonesNamespace
+}
+
+// DeletedEntries returns the tombstones of the entries in the previous build of the feed that are not in entries.
+// Newly deleted entries are deleted at Settings.Now(), while the previous tombstones are kept until they are
+// older than Settings.TombstoneDays, or their entry is back in entries.
+//
+// It returns nil if previous is nil or a different feed than the one with feedID.
+func (a *Renderer) DeletedEntries(feedID string, previous *Feed, entries []*Entry) []*DeletedEntry {
+	if previous == nil || previous.ID != feedID {
+		return nil
+	}
+
+	now := a.Settings.Now()
+	expiry := now.AddDate(0, 0, -a.Settings.TombstoneDaysDefaulted())
+	current := map[string]bool{}
+	for _, entry := range entries {
+		current[entry.ID] = true
+	}
+
+	var deletedEntries []*DeletedEntry
+	for _, deletedEntry := range previous.DeletedEntries {
+		if current[deletedEntry.Ref] || !deletedEntry.When.After(expiry) {
+			continue
+		}
+		current[deletedEntry.Ref] = true
+		deletedEntries = append(deletedEntries, deletedEntry)
+	}
+	for _, entry := range previous.Entries {
+		if current[entry.ID] {
+			continue
+		}
+		current[entry.ID] = true
+		deletedEntries = append(deletedEntries, &DeletedEntry{Ref: entry.ID, When: now, Links: alternateLinks(entry.Links)})
+	}
+	return deletedEntries
+}
+
+func alternateLinks(links []*Link) []*Link {
+	var alternates []*Link
+	for _, link := range links {
+		if link.Rel == "alternate" {
+			alternates = append(alternates, link)
+		}
+	}
+	return alternates
+}
+
+// RenderWithTombstones renders like Render, with tombstones of the entries that were deleted since the
+// previous build, which is the Atom xml data of the feed at selfURL. See Renderer.DeletedEntries.
+func (renderer *HTMLRenderer) RenderWithTombstones(feedName, selfURL, logoURL string, htmlEntries []*HTMLEntry, previous []byte) ([]byte, error) {
+	var previousFeed *Feed
+	if len(previous) > 0 {
+		var err error
+		previousFeed, err = Parse(bytes.NewReader(previous))
+		if err != nil {
+			return nil, fmt.Errorf("parse previous feed: %v", err)
+		}
+	}
+
+	htmlEntries, err := renderer.processEntries(htmlEntries)
+	if err != nil {
+		return nil, err
+	}
+	atomRenderer := renderer.atomRenderer()
+	feed := HTMLEntriesToFeed(atomRenderer, feedName, selfURL, logoURL, htmlEntries)
+
+	feed.SetDeletedEntries(atomRenderer.DeletedEntries(feed.ID, previousFeed, feed.Entries))
+	for _, deletedEntry := range feed.DeletedEntries {
+		if deletedEntry.When.After(feed.Updated) {
+			feed.Updated = deletedEntry.When
+		}
+	}
+	return renderer.marshall(feed)
+}
+
+func (v *validator) deletedEntries(deletedEntries []*DeletedEntry) {
+	for i, deletedEntry := range deletedEntries {
+		field := fmt.Sprintf("at:deleted-entry[%v]", i)
+		v.required(FeedIndex, field+".ref", deletedEntry.Ref)
+		if deletedEntry.When.IsZero() {
+			v.add(MissingViolation, FeedIndex, field+".when", "is required")
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package atom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func TestRenderer_DeletedEntries(t *testing.T) {
+	now := testClock()
+	recent := now.AddDate(0, 0, -DefaultTombstoneDays+1)
+	expired := now.AddDate(0, 0, -DefaultTombstoneDays)
+	entries := []*Entry{{ID: "a"}, {ID: "b"}}
+
+	testCases := []struct {
+		previous *Feed
+		exp      []*DeletedEntry
+	}{
+		{nil, nil},
+		{&Feed{ID: "other", Entries: []*Entry{{ID: "c"}}}, nil},
+		{&Feed{ID: "feed", Entries: []*Entry{{ID: "a"}, {ID: "b"}}}, nil},
+		{
+			&Feed{ID: "feed", Entries: []*Entry{{ID: "a"}, {ID: "c", Links: []*Link{{Rel: "self", Href: "s"}, {Rel: "alternate", Href: "c"}}}}},
+			[]*DeletedEntry{{Ref: "c", When: now, Links: []*Link{{Rel: "alternate", Href: "c"}}}},
+		},
+		{
+			&Feed{ID: "feed", DeletedEntries: []*DeletedEntry{{Ref: "c", When: recent}, {Ref: "d", When: expired}, {Ref: "a", When: recent}}},
+			[]*DeletedEntry{{Ref: "c", When: recent}},
+		},
+		{
+			&Feed{ID: "feed", DeletedEntries: []*DeletedEntry{{Ref: "c", When: recent}}, Entries: []*Entry{{ID: "c"}, {ID: "e"}}},
+			[]*DeletedEntry{{Ref: "c", When: recent}, {Ref: "e", When: now}},
+		},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+		})
+
+		settings := DefaultSettings()
+		settings.Clock = testClock
+		got := NewRenderer(settings).DeletedEntries("feed", tc.previous, entries)
+		if !cmp.Equal(got, tc.exp) {
+			t.Error(context.DiffString("Result", got, tc.exp, cmp.Diff(got, tc.exp)))
+		}
+	}
+}
+
+func TestHtmlRenderer_RenderWithTombstones(t *testing.T) {
+	htmlEntries := defaultHTMLEntries()
+	renderer := defaultHTMLRenderer()
+
+	previous, err := renderer.Render("posts", "posts.atom", "logo.png", htmlEntries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bytes, err := renderer.RenderWithTombstones("posts", "posts.atom", "logo.png", htmlEntries[1:], previous)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(bytes)
+	fixtureFilename := "tombstone.xml"
+	if *updateFixturesPtr {
+		test.WriteFixture(t, fixtureFilename, []byte(got))
+		return
+	}
+
+	exp := string(test.ReadFixture(t, fixtureFilename))
+	if got != exp {
+		t.Error(test.NewContext().DiffString("HTMLRenderer.RenderWithTombstones", got, exp, cmp.Diff(got, exp)))
+	}
+
+	renderer.Settings.Clock = func() time.Time {
+		return testClock().AddDate(0, 0, DefaultTombstoneDays)
+	}
+	bytes, err = renderer.RenderWithTombstones("posts", "posts.atom", "logo.png", htmlEntries[1:], bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp = string(mustRender(t, renderer, htmlEntries[1:]))
+	if string(bytes) != exp {
+		t.Error(test.NewContext().DiffString("expired tombstones", string(bytes), exp, cmp.Diff(string(bytes), exp)))
+	}
+
+	_, err = renderer.RenderWithTombstones("posts", "posts.atom", "logo.png", htmlEntries, []byte("not xml"))
+	if err == nil {
+		t.Error("expected error for invalid previous feed")
+	}
+}
+
+func mustRender(t *testing.T, renderer *HTMLRenderer, htmlEntries []*HTMLEntry) []byte {
+	bytes, err := renderer.Render("posts", "posts.atom", "logo.png", htmlEntries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes
+}
+
+func TestFeed_Validate_DeletedEntries(t *testing.T) {
+	feed := validFeed()
+	feed.SetDeletedEntries([]*DeletedEntry{{Ref: "tag:site.com,2018:/deleted", When: test.Time(1)}, {}})
+
+	var got []string
+	for _, violation := range feed.Validate() {
+		got = append(got, violation.Error())
+	}
+	exp := []string{"feed.at:deleted-entry[1].ref: is required", "feed.at:deleted-entry[1].when: is required"}
+	if !cmp.Equal(got, exp) {
+		t.Error(test.NewContext().DiffString("Validate", got, exp, cmp.Diff(got, exp)))
+	}
+}
@@ -0,0 +1,169 @@
+package atom
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// FeedIndex is the Violation.EntryIndex of violations on the Feed itself, instead of an Entry
+const FeedIndex = -1
+
+// ViolationKind is the kind of RFC 4287 rule a Violation breaks
+type ViolationKind string
+
+const (
+	// MissingViolation is a required element that is empty
+	MissingViolation ViolationKind = "missing"
+	// DuplicateIDViolation is an Entry.ID used by a previous Entry
+	DuplicateIDViolation ViolationKind = "duplicate_id"
+	// RelativeIRIViolation is a Link.Href, Author.URI or Contributor.URI that is not an absolute IRI
+	RelativeIRIViolation ViolationKind = "relative_iri"
+	// MissingAuthorViolation is an Entry without an Author, when the Feed has none
+	MissingAuthorViolation ViolationKind = "missing_author"
+	// UpdatedBeforePublishedViolation is an Entry updated before it was published
+	UpdatedBeforePublishedViolation ViolationKind = "updated_before_published"
+	// ContentTypeViolation is an EntryContent.Type that isn't text, html, xhtml or a MIME type,
+	// or content with an EntryContent.Src that isn't empty
+	ContentTypeViolation ViolationKind = "content_type"
+)
+
+// Violation represents a broken RFC 4287 rule in a Feed
+type Violation struct {
+	Kind ViolationKind
+	// EntryIndex is the index of the Entry in Feed.Entries, or FeedIndex
+	EntryIndex int
+	Field      string
+	Message    string
+}
+
+// Error returns the string representation of the Violation: entry[1].id: duplicate of entry[0]
+func (violation *Violation) Error() string {
+	location := "feed"
+	if violation.EntryIndex != FeedIndex {
+		location = fmt.Sprintf("entry[%v]", violation.EntryIndex)
+	}
+	return fmt.Sprintf("%v.%v: %v", location, violation.Field, violation.Message)
+}
+
+// Violations is a list of Violation, which can be returned as an error
+type Violations []*Violation
+
+// Error returns all the Violation strings joined together
+func (violations Violations) Error() string {
+	parts := make([]string, len(violations))
+	for i, violation := range violations {
+		parts[i] = violation.Error()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Validate checks the Feed against the rules of RFC 4287 that feed readers depend on.
+// It returns nil if there are no Violations.
+func (feed *Feed) Validate() Violations {
+	v := &validator{}
+
+	v.required(FeedIndex, "id", feed.ID)
+	v.required(FeedIndex, "title", feed.Title)
+	if feed.Updated.IsZero() {
+		v.add(MissingViolation, FeedIndex, "updated", "is required")
+	}
+	v.people(FeedIndex, feed.Authors, feed.Contributors)
+	v.links(FeedIndex, feed.Links)
+
+	ids := map[string]int{}
+	for i, entry := range feed.Entries {
+		if previous, contains := ids[entry.ID]; contains && entry.ID != "" {
+			v.add(DuplicateIDViolation, i, "id", "duplicate of entry[%v]", previous)
+		} else {
+			ids[entry.ID] = i
+		}
+		v.entry(i, entry, len(feed.Authors) > 0)
+		v.inReplyTo(i, entry.InReplyTo)
+	}
+	if feed.Podcast != nil {
+		v.podcast(feed)
+	}
+	v.deletedEntries(feed.DeletedEntries)
+	return v.violations
+}
+
+type validator struct {
+	violations Violations
+}
+
+func (v *validator) add(kind ViolationKind, entryIndex int, field, format string, args ...interface{}) {
+	v.violations = append(v.violations, &Violation{kind, entryIndex, field, fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(entryIndex int, field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(MissingViolation, entryIndex, field, "is required")
+	}
+}
+
+func (v *validator) iri(entryIndex int, field, value string) {
+	u, err := url.Parse(value)
+	if err != nil || !u.IsAbs() {
+		v.add(RelativeIRIViolation, entryIndex, field, "%q is not an absolute IRI", value)
+	}
+}
+
+func (v *validator) people(entryIndex int, authors []*Author, contributors []*Contributor) {
+	for i, author := range authors {
+		v.person(entryIndex, fmt.Sprintf("author[%v]", i), author.Name, author.URI)
+	}
+	for i, contributor := range contributors {
+		v.person(entryIndex, fmt.Sprintf("contributor[%v]", i), contributor.Name, contributor.URI)
+	}
+}
+
+func (v *validator) person(entryIndex int, field, name, uri string) {
+	v.required(entryIndex, field+".name", name)
+	if uri != "" {
+		v.iri(entryIndex, field+".uri", uri)
+	}
+}
+
+func (v *validator) links(entryIndex int, links []*Link) {
+	for i, link := range links {
+		field := fmt.Sprintf("link[%v].href", i)
+		if link.Href == "" {
+			v.add(MissingViolation, entryIndex, field, "is required")
+			continue
+		}
+		v.iri(entryIndex, field, link.Href)
+	}
+}
+
+func (v *validator) entry(entryIndex int, entry *Entry, feedHasAuthor bool) {
+	v.required(entryIndex, "id", entry.ID)
+	v.required(entryIndex, "title", entry.Title)
+	if entry.Updated.IsZero() {
+		v.add(MissingViolation, entryIndex, "updated", "is required")
+	}
+
+	if len(entry.Authors) == 0 && !feedHasAuthor {
+		v.add(MissingAuthorViolation, entryIndex, "author", "is required when the feed has no author")
+	}
+	v.people(entryIndex, entry.Authors, entry.Contributors)
+	v.links(entryIndex, entry.Links)
+
+	if !entry.Published.IsZero() && entry.Updated.Before(entry.Published) {
+		v.add(UpdatedBeforePublishedViolation, entryIndex, "updated", "%v is before published %v", entry.Updated, entry.Published)
+	}
+	if entry.Content != nil {
+		v.content(entryIndex, entry)
+	}
+}
+
+func (v *validator) content(entryIndex int, entry *Entry) {
+	kind, err := entry.Content.kind()
+	if err != nil {
+		v.add(ContentTypeViolation, entryIndex, "content.type", "%v", err)
+		return
+	}
+	if kind == outOfLineContent || kind == base64Content {
+		v.required(entryIndex, "summary", entry.Summary)
+	}
+}
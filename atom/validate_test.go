@@ -0,0 +1,136 @@
+package atom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func validFeed() *Feed {
+	htmlEntries := []*HTMLEntry{
+		{ID: "first", Title: "num #1", Updated: test.Time(3), HTMLContent: "<p>The story starts here</p>", Summary: "The sum", Published: test.Time(2)},
+		{ID: "second", Title: "num #2", Updated: test.Time(5), HTMLContent: "<p>The story ends here</p>", Summary: "The sum of it all", Published: test.Time(4)},
+	}
+	return HTMLEntriesToFeed(defaultRenderer(), "posts", "posts.atom", "logo.png", htmlEntries)
+}
+
+type violationKey struct {
+	Kind       ViolationKind
+	EntryIndex int
+	Field      string
+}
+
+func TestFeed_Validate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mutate func(feed *Feed)
+		exp    []violationKey
+	}{
+		{"valid", func(feed *Feed) {}, nil},
+		{"feed required", func(feed *Feed) {
+			feed.ID = ""
+			feed.Title = " "
+			feed.Updated = time.Time{}
+		}, []violationKey{
+			{MissingViolation, FeedIndex, "id"},
+			{MissingViolation, FeedIndex, "title"},
+			{MissingViolation, FeedIndex, "updated"},
+		}},
+		{"entry required", func(feed *Feed) {
+			feed.Entries[1].ID = ""
+			feed.Entries[1].Title = ""
+			feed.Entries[1].Updated = time.Time{}
+			feed.Entries[1].Published = time.Time{}
+		}, []violationKey{
+			{MissingViolation, 1, "id"},
+			{MissingViolation, 1, "title"},
+			{MissingViolation, 1, "updated"},
+		}},
+		{"duplicate id", func(feed *Feed) {
+			feed.Entries[1].ID = feed.Entries[0].ID
+		}, []violationKey{
+			{DuplicateIDViolation, 1, "id"},
+		}},
+		{"relative iri", func(feed *Feed) {
+			feed.Authors[0].URI = "/about"
+			feed.Links[0].Href = "posts.atom"
+			feed.Links[1].Href = ""
+			feed.Entries[0].Links[0].Href = "first"
+		}, []violationKey{
+			{RelativeIRIViolation, FeedIndex, "author[0].uri"},
+			{RelativeIRIViolation, FeedIndex, "link[0].href"},
+			{MissingViolation, FeedIndex, "link[1].href"},
+			{RelativeIRIViolation, 0, "link[0].href"},
+		}},
+		{"missing author", func(feed *Feed) {
+			feed.Authors = nil
+			feed.Entries[1].Authors = nil
+		}, []violationKey{
+			{MissingAuthorViolation, 1, "author"},
+		}},
+		{"entry author", func(feed *Feed) {
+			feed.Entries[0].Authors = []*Author{{Name: "Guest"}, {URI: "guest.com"}}
+			feed.Entries[0].Contributors = []*Contributor{{Name: "Helper", URI: "/helper"}}
+		}, []violationKey{
+			{MissingViolation, 0, "author[1].name"},
+			{RelativeIRIViolation, 0, "author[1].uri"},
+			{RelativeIRIViolation, 0, "contributor[0].uri"},
+		}},
+		{"updated before published", func(feed *Feed) {
+			feed.Entries[0].Published = test.Time(4)
+		}, []violationKey{
+			{UpdatedBeforePublishedViolation, 0, "updated"},
+		}},
+		{"content type", func(feed *Feed) {
+			feed.Entries[0].Content.Type = "image/png"
+			feed.Entries[1].Content.Type = "markdown"
+		}, []violationKey{
+			{ContentTypeViolation, 1, "content.type"},
+		}},
+		{"out-of-line content", func(feed *Feed) {
+			feed.Entries[0].Content = NewOutOfLineContent("video/mp4", "https://yourwebsite.com/first.mp4")
+			feed.Entries[1].Content = NewOutOfLineContent("html", "https://yourwebsite.com/second")
+			feed.Entries[1].Summary = ""
+		}, []violationKey{
+			{ContentTypeViolation, 1, "content.type"},
+		}},
+		{"summary required", func(feed *Feed) {
+			feed.Entries[0].Content = NewBase64Content("image/png", []byte("png"))
+			feed.Entries[0].Summary = ""
+			feed.Entries[1].Content = NewOutOfLineContent("video/mp4", "https://yourwebsite.com/second.mp4")
+			feed.Entries[1].Summary = ""
+		}, []violationKey{
+			{MissingViolation, 0, "summary"},
+			{MissingViolation, 1, "summary"},
+		}},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index": testCaseIndex,
+			"name":  tc.name,
+		})
+
+		feed := validFeed()
+		tc.mutate(feed)
+
+		var got []violationKey
+		for _, violation := range feed.Validate() {
+			got = append(got, violationKey{violation.Kind, violation.EntryIndex, violation.Field})
+		}
+		if !cmp.Equal(got, tc.exp) {
+			t.Error(context.DiffString("Result", got, tc.exp, cmp.Diff(got, tc.exp)))
+		}
+	}
+}
+
+func TestViolations_Error(t *testing.T) {
+	violations := Violations{
+		{MissingViolation, FeedIndex, "id", "is required"},
+		{DuplicateIDViolation, 1, "id", "duplicate of entry[0]"},
+	}
+	test.AssertLabel(t, "Result", violations.Error(), "feed.id: is required; entry[1].id: duplicate of entry[0]")
+}
@@ -0,0 +1,128 @@
+package atom
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PublishRetries is the default number of times a failed hub notification is retried
+const PublishRetries = 3
+
+// PublishRetryDelay is the default delay before the first retry of a failed hub notification
+const PublishRetryDelay = time.Second
+
+// PublishTimeout is the default timeout of a hub notification request
+const PublishTimeout = 10 * time.Second
+
+// Publisher is a "main struct", which notifies the WebSub hubs in Settings.Hubs when feeds change
+type Publisher struct {
+	Settings *Settings
+	// Client sends the notifications, its Timeout is PublishTimeout by default,
+	// so a hub that doesn't respond fails and is retried instead of blocking
+	Client *http.Client
+	// Retries is the number of times a failed notification is retried
+	Retries int
+	// RetryDelay is the delay before the first retry, it doubles after each retry
+	RetryDelay time.Duration
+}
+
+// NewPublisher returns a new instance of Publisher
+func NewPublisher(settings *Settings) *Publisher {
+	return &Publisher{
+		Settings:   settings,
+		Client:     &http.Client{Timeout: PublishTimeout},
+		Retries:    PublishRetries,
+		RetryDelay: PublishRetryDelay,
+	}
+}
+
+// PublishError is a failed notification of a hub
+type PublishError struct {
+	Hub     string
+	FeedURL string
+	Err     error
+}
+
+// Error returns the error message of the PublishError
+func (publishError *PublishError) Error() string {
+	return fmt.Sprintf("publish %v to %v: %v", publishError.FeedURL, publishError.Hub, publishError.Err)
+}
+
+// PublishErrors are all the failed notifications of Publisher.Publish
+type PublishErrors []*PublishError
+
+// Error returns the error messages of all the PublishErrors
+func (publishErrors PublishErrors) Error() string {
+	messages := make([]string, len(publishErrors))
+	for i, publishError := range publishErrors {
+		messages[i] = publishError.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Publish POSTs hub.mode=publish to every hub for each of the changed feeds at selfURLs. Each notification is
+// retried with Retries and RetryDelay. All notifications are attempted and the failures returned as PublishErrors.
+func (publisher *Publisher) Publish(selfURLs ...string) error {
+	var publishErrors PublishErrors
+	for _, hub := range publisher.Settings.Hubs {
+		for _, selfURL := range selfURLs {
+			feedURL := publisher.Settings.FullURLFor(selfURL)
+			err := publisher.notify(hub, feedURL)
+			if err != nil {
+				publishErrors = append(publishErrors, &PublishError{hub, feedURL, err})
+			}
+		}
+	}
+	if len(publishErrors) == 0 {
+		return nil
+	}
+	return publishErrors
+}
+
+func (publisher *Publisher) notify(hub, feedURL string) error {
+	delay := publisher.RetryDelay
+	var err error
+	for attempt := 0; attempt <= publisher.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		var retry bool
+		retry, err = publisher.post(hub, feedURL)
+		if err == nil || !retry {
+			return err
+		}
+	}
+	return err
+}
+
+// post sends a single notification, returning if the failure can be retried
+func (publisher *Publisher) post(hub, feedURL string) (retry bool, err error) {
+	response, err := publisher.Client.PostForm(hub, url.Values{"hub.mode": {"publish"}, "hub.url": {feedURL}})
+	if err != nil {
+		return true, err
+	}
+	defer func() {
+		closeErr := response.Body.Close()
+		if err == nil && closeErr != nil {
+			retry, err = true, closeErr
+		}
+	}()
+	_, err = io.Copy(ioutil.Discard, response.Body)
+	if err != nil {
+		return true, err
+	}
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return false, nil
+	}
+	// hubs rejecting the request won't accept it on a retry, except when rate limiting
+	retry = response.StatusCode >= 500 || response.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("hub responded with %v", response.Status)
+}
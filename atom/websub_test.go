@@ -0,0 +1,180 @@
+package atom
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/s12chung/gostatic/go/test"
+)
+
+func TestRenderer_NewFeed_Hubs(t *testing.T) {
+	settings := DefaultSettings()
+	settings.Hubs = []string{"https://hub.one.com", "https://hub.two.com/websub"}
+	feed := NewRenderer(settings).NewFeed("posts", test.Time(1), "posts.atom", "logo.png")
+
+	var got []string
+	for _, link := range feed.Links {
+		if link.Rel == "hub" {
+			got = append(got, link.Href)
+		}
+	}
+	if !cmp.Equal(got, settings.Hubs) {
+		t.Error(test.NewContext().DiffString("Hub links", got, settings.Hubs, cmp.Diff(got, settings.Hubs)))
+	}
+}
+
+type testHub struct {
+	statuses []int
+
+	mutex    sync.Mutex
+	requests []string
+}
+
+func newTestHub(t *testing.T, statuses ...int) (*testHub, *httptest.Server) {
+	hub := &testHub{statuses: statuses}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Error(test.NewContext().GotExpString("r.Method", r.Method, http.MethodPost))
+		}
+		err := r.ParseForm()
+		if err != nil {
+			t.Error(err)
+		}
+		if got := r.PostForm.Get("hub.mode"); got != "publish" {
+			t.Error(test.NewContext().GotExpString("hub.mode", got, "publish"))
+		}
+
+		hub.mutex.Lock()
+		defer hub.mutex.Unlock()
+		status := http.StatusNoContent
+		if len(hub.requests) < len(hub.statuses) {
+			status = hub.statuses[len(hub.requests)]
+		}
+		hub.requests = append(hub.requests, r.PostForm.Get("hub.url"))
+		w.WriteHeader(status)
+	}))
+	return hub, server
+}
+
+func TestPublisher_Publish(t *testing.T) {
+	testCases := []struct {
+		statuses    []int
+		retries     int
+		expRequests int
+		expErrors   int
+	}{
+		{nil, 0, 2, 0},
+		{[]int{http.StatusAccepted, http.StatusOK}, 0, 2, 0},
+		{[]int{http.StatusInternalServerError, http.StatusServiceUnavailable}, 2, 4, 0},
+		{[]int{http.StatusTooManyRequests}, 1, 3, 0},
+		{[]int{http.StatusInternalServerError, http.StatusInternalServerError}, 1, 3, 1},
+		{[]int{http.StatusBadRequest}, 3, 2, 1},
+		{[]int{http.StatusNotFound, http.StatusNotFound}, 3, 2, 2},
+	}
+
+	for testCaseIndex, tc := range testCases {
+		context := test.NewContext().SetFields(test.ContextFields{
+			"index":    testCaseIndex,
+			"statuses": tc.statuses,
+			"retries":  tc.retries,
+		})
+
+		hub, server := newTestHub(t, tc.statuses...)
+		settings := DefaultSettings()
+		settings.Hubs = []string{server.URL}
+		publisher := NewPublisher(settings)
+		publisher.Retries = tc.retries
+		publisher.RetryDelay = 0
+
+		err := publisher.Publish("posts.atom", "/tags/go.atom")
+		server.Close()
+
+		if len(hub.requests) != tc.expRequests {
+			t.Error(context.GotExpString("len(requests)", len(hub.requests), tc.expRequests))
+		}
+		got := 0
+		if err != nil {
+			publishErrors, ok := err.(PublishErrors)
+			if !ok {
+				t.Error(context.Stringf("err is not PublishErrors: %v", err))
+			}
+			got = len(publishErrors)
+		}
+		if got != tc.expErrors {
+			t.Error(context.GotExpString("len(PublishErrors)", got, tc.expErrors))
+		}
+	}
+}
+
+func TestPublisher_Publish_FeedURLs(t *testing.T) {
+	hub, server := newTestHub(t)
+	defer server.Close()
+
+	settings := DefaultSettings()
+	settings.Hubs = []string{server.URL, server.URL + "/second"}
+	err := NewPublisher(settings).Publish("posts.atom", "/tags/go.atom")
+	if err != nil {
+		t.Error(err)
+	}
+
+	exp := []string{
+		"https://yourwebsite.com/posts.atom",
+		"https://yourwebsite.com/tags/go.atom",
+		"https://yourwebsite.com/posts.atom",
+		"https://yourwebsite.com/tags/go.atom",
+	}
+	if !cmp.Equal(hub.requests, exp) {
+		t.Error(test.NewContext().DiffString("hub.url", hub.requests, exp, cmp.Diff(hub.requests, exp)))
+	}
+}
+
+func TestPublisher_Publish_Unreachable(t *testing.T) {
+	_, server := newTestHub(t)
+	server.Close()
+
+	settings := DefaultSettings()
+	settings.Hubs = []string{server.URL}
+	publisher := NewPublisher(settings)
+	publisher.RetryDelay = 0
+
+	err := publisher.Publish("posts.atom")
+	publishErrors, ok := err.(PublishErrors)
+	if !ok || len(publishErrors) != 1 {
+		t.Fatalf("expected 1 PublishError, got: %v", err)
+	}
+	publishError := publishErrors[0]
+	if publishError.Hub != server.URL || publishError.FeedURL != "https://yourwebsite.com/posts.atom" {
+		t.Errorf("unexpected PublishError: %v", publishError)
+	}
+}
+
+func TestPublisher_Publish_Timeout(t *testing.T) {
+	test.AssertLabel(t, "Client.Timeout", NewPublisher(DefaultSettings()).Client.Timeout, PublishTimeout)
+
+	done := make(chan struct{})
+	requests := make(chan struct{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- struct{}{}
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	settings := DefaultSettings()
+	settings.Hubs = []string{server.URL}
+	publisher := NewPublisher(settings)
+	publisher.Client.Timeout = 10 * time.Millisecond
+	publisher.Retries = 1
+	publisher.RetryDelay = 0
+
+	err := publisher.Publish("posts.atom")
+	if _, ok := err.(PublishErrors); !ok {
+		t.Errorf("expected PublishErrors, got: %v", err)
+	}
+	test.AssertLabel(t, "requests", len(requests), 2)
+}